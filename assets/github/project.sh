@@ -0,0 +1,63 @@
+#!/usr/bin/env bash
+# Creates or updates the GitHub project (v2) of a repository, and prints its ID.
+# The project is created and linked to the repository on the first run, and updated in place afterwards,
+# where its ID is the output of the previous run; custom fields are created if missing, but never deleted.
+# Requires the GitHub CLI authenticated via GH_TOKEN or GITHUB_TOKEN, and jq.
+#
+# PROJECT_OWNER: the login of the repository's owner
+# PROJECT_REPOSITORY_ID: the node ID of the repository
+# PROJECT_TITLE: the title of the project
+# PROJECT_DESCRIPTION: the short description of the project
+# PROJECT_STATUS_OPTIONS: the options of the 'Status' field as a JSON array of ProjectV2SingleSelectFieldOptionInput
+# PROJECT_FIELDS: the custom fields as a JSON array of {name, dataType, singleSelectOptions}
+set -euo pipefail
+
+# graphql runs a GraphQL query with the given variables, and prints the result of the given jq filter.
+graphql() {
+  jq -n --arg query "$1" --argjson variables "$2" '{query: $query, variables: $variables}' |
+    gh api graphql --input - --jq "$3"
+}
+
+project="${PULUMI_COMMAND_STDOUT:-}"
+if [ -z "${project}" ]; then
+  owner=$(graphql 'query($login: String!) { repositoryOwner(login: $login) { id } }' \
+    "$(jq -n --arg login "${PROJECT_OWNER}" '{login: $login}')" \
+    '.data.repositoryOwner.id')
+  project=$(graphql 'mutation($input: CreateProjectV2Input!) { createProjectV2(input: $input) { projectV2 { id } } }' \
+    "$(jq -n --arg owner "${owner}" --arg repository "${PROJECT_REPOSITORY_ID}" --arg title "${PROJECT_TITLE}" \
+      '{input: {ownerId: $owner, repositoryId: $repository, title: $title}}')" \
+    '.data.createProjectV2.projectV2.id')
+fi
+
+graphql 'mutation($input: UpdateProjectV2Input!) { updateProjectV2(input: $input) { projectV2 { id } } }' \
+  "$(jq -n --arg project "${project}" --arg title "${PROJECT_TITLE}" --arg description "${PROJECT_DESCRIPTION}" \
+    '{input: {projectId: $project, title: $title, shortDescription: $description}}')" \
+  '.data.updateProjectV2.projectV2.id' >/dev/null
+
+# the IDs of the existing fields keyed by their name
+fields=$(graphql 'query($project: ID!) { node(id: $project) { ... on ProjectV2 { fields(first: 100) { nodes { ... on ProjectV2FieldCommon { id name } } } } } }' \
+  "$(jq -n --arg project "${project}" '{project: $project}')" \
+  '[.data.node.fields.nodes[] | {key: .name, value: .id}] | from_entries')
+
+# updateOptions replaces the options of the single select field with the given ID.
+updateOptions() {
+  graphql 'mutation($input: UpdateProjectV2FieldInput!) { updateProjectV2Field(input: $input) { projectV2Field { ... on ProjectV2SingleSelectField { id } } } }' \
+    "$(jq -n --arg field "$1" --argjson options "$2" '{input: {fieldId: $field, singleSelectOptions: $options}}')" \
+    '.data.updateProjectV2Field.projectV2Field.id' >/dev/null
+}
+
+updateOptions "$(jq -r '.Status' <<<"${fields}")" "${PROJECT_STATUS_OPTIONS}"
+
+while read -r field; do
+  name=$(jq -r '.name' <<<"${field}")
+  id=$(jq -r --arg name "${name}" '.[$name] // empty' <<<"${fields}")
+  if [ -z "${id}" ]; then
+    graphql 'mutation($input: CreateProjectV2FieldInput!) { createProjectV2Field(input: $input) { projectV2Field { ... on ProjectV2FieldCommon { id } } } }' \
+      "$(jq -n --arg project "${project}" --argjson field "${field}" '{input: ({projectId: $project} + $field)}')" \
+      '.data.createProjectV2Field.projectV2Field.id' >/dev/null
+  elif [ "$(jq -r '.dataType' <<<"${field}")" = "SINGLE_SELECT" ]; then
+    updateOptions "${id}" "$(jq -c '.singleSelectOptions' <<<"${field}")"
+  fi
+done < <(jq -c '.[]' <<<"${PROJECT_FIELDS}")
+
+printf '%s' "${project}"
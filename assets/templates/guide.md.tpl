@@ -0,0 +1,93 @@
+# {{ .repository }}
+
+This guide is generated from the repository configuration; do not edit it manually.
+{{- if not .secrets }}
+{{- if eq .sink "vault" }}
+
+No Vault mount is configured for this repository, hence no credentials are provided.
+{{- else }}
+
+No integrations are configured for this repository, hence no credentials are provided.
+{{- end }}
+{{- else }}
+
+## Secrets
+{{- if eq .sink "vault" }}
+
+The credentials of this repository are stored in the Vault KV mount `{{ .mount }}`.
+The GitHub Actions secrets `VAULT_ADDR`, `VAULT_ROLE`, and `VAULT_PATH` contain everything needed to authenticate to Vault via GitHub OIDC.
+{{- if .roles }}
+The scoped roles, which are only assumable by the configured refs, environments, or workflows, are contained in {{ range $i, $secret := .roles }}{{ if $i }}, {{ end }}`{{ $secret }}`{{ end }}.
+{{- end }}
+
+| Path | Fields | Description |
+| ---- | ------ | ----------- |
+{{- range .secrets }}
+| `{{ $.mount }}/{{ .Key }}` | {{ range $i, $field := .Fields }}{{ if $i }}, {{ end }}`{{ $field.Name }}`{{ end }} | {{ .Description }} |
+{{- end }}
+{{- else }}
+
+The credentials of this repository are stored as GitHub Actions {{ if eq .sink "variables" }}variables, and their sensitive fields as secrets{{ else }}secrets{{ end }}.
+
+| Key | Fields | Description |
+| --- | ------ | ----------- |
+{{- range .secrets }}
+| `{{ .Key }}` | {{ range $i, $field := .Fields }}{{ if $i }}, {{ end }}`{{ index $.refs $field.Env }}`{{ end }} | {{ .Description }} |
+{{- end }}
+{{- end }}
+
+## GitHub Actions
+
+The following job {{ if eq .sink "vault" }}authenticates to Vault, {{ end }}exports the credentials as environment variables, and authenticates to every configured cloud:
+
+```yaml
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: read
+      id-token: write
+{{- if ne .sink "vault" }}
+    env:
+{{- range .secrets }}{{ range .Fields }}
+      {{ .Env }}: ${{"{{"}} {{ index $.refs .Env }} {{"}}"}}
+{{- end }}{{ end }}
+{{- end }}
+    steps:
+      - uses: actions/checkout@v4
+{{- if eq .sink "vault" }}
+      - id: vault
+        uses: hashicorp/vault-action@v3
+        with:
+          url: ${{"{{"}} secrets.VAULT_ADDR {{"}}"}}
+          method: jwt
+          path: ${{"{{"}} secrets.VAULT_PATH {{"}}"}}
+          role: ${{"{{"}} secrets.VAULT_ROLE {{"}}"}}
+          secrets: |
+{{- range .secrets }}{{ if ne .Key "vault" }}{{ $key := .Key }}{{ range .Fields }}
+            {{ $.mount }}/data/{{ $key }} {{ .Name }} | {{ .Env }} ;
+{{- end }}{{ end }}{{ end }}
+{{- end }}
+{{- if index .has "aws" }}
+      - uses: aws-actions/configure-aws-credentials@v4
+        with:
+          role-to-assume: ${{"{{"}} {{ index .refs "AWS_ROLE_ARN" }} {{"}}"}}
+          aws-region: ${{"{{"}} {{ index .refs "AWS_REGION" }} {{"}}"}}
+{{- end }}
+{{- if index .has "google-cloud" }}
+      - uses: google-github-actions/auth@v2
+        with:
+          workload_identity_provider: ${{"{{"}} {{ index .refs "GOOGLE_WORKLOAD_IDENTITY_PROVIDER" }} {{"}}"}}
+          service_account: ${{"{{"}} {{ index .refs "GOOGLE_SERVICE_ACCOUNT" }} {{"}}"}}
+{{- end }}
+{{- if index .has "tailscale" }}
+      - uses: tailscale/github-action@v3
+        with:
+          oauth-client-id: ${{"{{"}} {{ index .refs "TS_OAUTH_CLIENT_ID" }} {{"}}"}}
+          oauth-secret: ${{"{{"}} {{ index .refs "TS_OAUTH_SECRET" }} {{"}}"}}
+          tags: tag:ci
+{{- end }}
+```
+
+Every field is also exported as the environment variable listed above to {{ if eq .sink "vault" }}all subsequent steps{{ else }}all steps{{ end }} of the job.
+{{- end }}
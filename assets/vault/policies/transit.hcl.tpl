@@ -0,0 +1,6 @@
+path "transit/encrypt/github-{{ .repository }}" {
+  capabilities = ["update"]
+}
+path "transit/decrypt/github-{{ .repository }}" {
+  capabilities = ["update"]
+}
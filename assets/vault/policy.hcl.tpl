@@ -5,7 +5,15 @@ path "github-{{ .repository }}/*" {
   capabilities = ["read", "list"]
 }
 {{- range .additionalPaths }}
-path "{{ .path }}/*" {
+path {{ .path }} {
   capabilities = [{{ .permissions }}]
 }
 {{- end }}
+{{- range .paths }}
+path {{ .path }} {
+  capabilities = [{{ .capabilities }}]
+{{- if .requiredParameters }}
+  required_parameters = [{{ .requiredParameters }}]
+{{- end }}
+}
+{{- end }}
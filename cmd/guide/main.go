@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/guide"
+	"github.com/muhlba91/github-infrastructure/pkg/util"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// main is the entry point of the usage guide generator.
+// It renders a Markdown usage guide per repository listing the secrets provisioned in its credential sink,
+// and a GitHub Actions job using them, without any credentials or network access.
+func main() {
+	stackName := flag.String("stack", "prod", "the name of the Pulumi stack to generate the guides for")
+	projectDir := flag.String("project", ".", "the directory containing the Pulumi project and stack files")
+	repositoriesDir := flag.String("repositories", "./assets/repositories", "the directory containing the repositories")
+	profilesDir := flag.String("profiles", "./assets/templates/profiles", "the directory containing the profiles")
+	outputDir := flag.String("output", "./docs/repositories", "the directory to write the guides to")
+	flag.Parse()
+
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	stackConfig, sErr := util.ParseStackConfig(*projectDir, *stackName)
+	if sErr != nil {
+		fail(sErr)
+	}
+
+	var repositoriesDefaults map[string]any
+	if stackConfig.Repositories != nil {
+		repositoriesDefaults = stackConfig.Repositories.Defaults
+	}
+
+	repos, rErr := util.ParseRepositoriesFromFiles(*repositoriesDir, *profilesDir, repositoriesDefaults)
+	if rErr != nil {
+		fail(rErr)
+	}
+
+	if mErr := os.MkdirAll(*outputDir, 0o755); mErr != nil { //nolint:gosec // the guides are not sensitive
+		fail(mErr)
+	}
+	for _, repo := range repos {
+		doc, gErr := guide.Render(repo, stackConfig)
+		if gErr != nil {
+			fail(gErr)
+		}
+
+		file := filepath.Join(*outputDir, fmt.Sprintf("%s.md", repo.Name))
+		if wErr := os.WriteFile(file, []byte(doc), 0o644); wErr != nil { //nolint:gosec // the guides are not sensitive
+			fail(wErr)
+		}
+	}
+
+	fmt.Fprintf(os.Stdout, "%d usage guides written to: %s\n", len(repos), *outputDir)
+}
+
+// fail prints the given error and exits with a non-zero exit code.
+// err: The error to print.
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
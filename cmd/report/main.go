@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/report"
+	"github.com/muhlba91/github-infrastructure/pkg/util"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// main is the entry point of the access matrix report.
+// It reports the effective permissions of every repository on every Google Cloud project, AWS account,
+// and Scaleway organization and project, without any credentials or network access.
+func main() {
+	stackName := flag.String("stack", "prod", "the name of the Pulumi stack to report the access matrix for")
+	projectDir := flag.String("project", ".", "the directory containing the Pulumi project and stack files")
+	repositoriesDir := flag.String("repositories", "./assets/repositories", "the directory containing the repositories")
+	profilesDir := flag.String("profiles", "./assets/templates/profiles", "the directory containing the profiles")
+	format := flag.String("format", report.FormatMarkdown, "the output format: json, csv, or markdown")
+	output := flag.String("output", "", "the file to write the report to (default: stdout)")
+	flag.Parse()
+
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	stackConfig, sErr := util.ParseStackConfig(*projectDir, *stackName)
+	if sErr != nil {
+		fail(sErr)
+	}
+
+	var repositoriesDefaults map[string]any
+	if stackConfig.Repositories != nil {
+		repositoriesDefaults = stackConfig.Repositories.Defaults
+	}
+
+	repos, rErr := util.ParseRepositoriesFromFiles(*repositoriesDir, *profilesDir, repositoriesDefaults)
+	if rErr != nil {
+		fail(rErr)
+	}
+
+	entries, bErr := report.Build(repos, stackConfig)
+	if bErr != nil {
+		fail(bErr)
+	}
+
+	if *output == "" {
+		if wErr := report.Write(os.Stdout, entries, *format); wErr != nil {
+			fail(wErr)
+		}
+		return
+	}
+
+	file, cErr := os.Create(*output)
+	if cErr != nil {
+		fail(cErr)
+	}
+	wErr := report.Write(file, entries, *format)
+	if clErr := file.Close(); wErr == nil {
+		wErr = clErr
+	}
+	if wErr != nil {
+		fail(wErr)
+	}
+}
+
+// fail prints the given error and exits with a non-zero exit code.
+// err: The error to print.
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/guardrail"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/validation"
+	"github.com/muhlba91/github-infrastructure/pkg/util"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// main is the entry point of the offline validation command.
+// It cross-checks the repository configurations against the configuration of a stack without any credentials
+// or network access, and exits with a non-zero exit code if any error is found.
+func main() {
+	stackName := flag.String("stack", "prod", "the name of the Pulumi stack to validate against")
+	projectDir := flag.String("project", ".", "the directory containing the Pulumi project and stack files")
+	repositoriesDir := flag.String("repositories", "./assets/repositories", "the directory containing the repositories")
+	profilesDir := flag.String("profiles", "./assets/templates/profiles", "the directory containing the profiles")
+	guardrailsFile := flag.String("guardrails", "./assets/guardrails.yml", "the file containing the guardrail rules")
+	flag.Parse()
+
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	stackConfig, sErr := util.ParseStackConfig(*projectDir, *stackName)
+	if sErr != nil {
+		fail(sErr)
+	}
+
+	var repositoriesDefaults map[string]any
+	if stackConfig.Repositories != nil {
+		repositoriesDefaults = stackConfig.Repositories.Defaults
+	}
+
+	repos, rErr := util.ParseRepositoriesFromFiles(*repositoriesDir, *profilesDir, repositoriesDefaults)
+	if rErr != nil {
+		fail(rErr)
+	}
+
+	guardrails, gErr := guardrail.Load(*guardrailsFile)
+	if gErr != nil {
+		fail(gErr)
+	}
+
+	errs := validation.Repositories(repos, stackConfig)
+	violations, vErr := guardrail.Evaluate(guardrails, repos, stackConfig)
+	if vErr != nil {
+		fail(vErr)
+	}
+	for _, violation := range violations {
+		if violation.Level == guardrail.LevelDeny {
+			errs = append(errs, violation)
+			continue
+		}
+		log.Warn().Msgf("[validate] %s", violation)
+	}
+	for _, err := range errs {
+		log.Error().Msgf("[validate] %s", err)
+	}
+	if len(errs) > 0 {
+		fmt.Fprintf(os.Stderr, "%d error(s) found in %d repositories\n", len(errs), len(repos))
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stdout, "%d repositories are valid for stack: %s\n", len(repos), *stackName)
+}
+
+// fail prints the given error and exits with a non-zero exit code.
+// err: The error to print.
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -1,10 +1,10 @@
 package aws
 
 import (
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
 	awsModel "github.com/muhlba91/github-infrastructure/pkg/model/aws"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
 	"github.com/pulumi/pulumi-aws/sdk/v7/go/aws"
-	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/rs/zerolog/log"
 )
@@ -13,32 +13,33 @@ import (
 // ctx: Pulumi context for resource management.
 // account: AWS repository account configuration.
 // identityProviderArn: ARN of the identity provider associated with the account.
-// vaultStore: Vault mount configuration for secrets management.
+// sink: The credential sink of the repository.
 // repositoriesConfig: Repository configuration details.
 // awsConfig: AWS configuration details.
 // provider: Pulumi AWS provider for resource creation.
 func configureAccount(ctx *pulumi.Context,
 	account *awsModel.RepositoryAccount,
 	identityProviderArn *pulumi.StringOutput,
-	vaultStore *vault.Mount,
+	sink integration.Sink,
 	repositoriesConfig *repositories.Config,
 	provider *aws.Provider,
-) pulumi.Output {
-	return identityProviderArn.ApplyT(func(arn string) error {
-		_, err := createAccountIAM(
-			ctx,
-			account,
-			arn,
-			vaultStore,
-			repositoriesConfig,
-			provider,
-		)
-		if err != nil {
-			log.Err(err).
-				Msgf("[aws][account] error configuring AWS IAM for repository account: %s", *account.Repository)
-			return err
-		}
+) (pulumi.Map, error) {
+	role, err := createAccountIAM(
+		ctx,
+		account,
+		*identityProviderArn,
+		sink,
+		repositoriesConfig,
+		provider,
+	)
+	if err != nil {
+		log.Err(err).
+			Msgf("[aws][account] error configuring AWS IAM for repository account: %s", *account.Repository)
+		return nil, err
+	}
 
-		return nil
-	})
+	return pulumi.Map{
+		"account": pulumi.String(*account.ID),
+		"roleArn": role.Arn,
+	}, nil
 }
@@ -4,15 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
 	"github.com/muhlba91/github-infrastructure/pkg/lib/config"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
 	awsModel "github.com/muhlba91/github-infrastructure/pkg/model/aws"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
 	"github.com/muhlba91/pulumi-shared-library/pkg/lib/random"
-	"github.com/muhlba91/pulumi-shared-library/pkg/lib/vault/secret"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/metadata"
 	"github.com/pulumi/pulumi-aws/sdk/v7/go/aws"
 	"github.com/pulumi/pulumi-aws/sdk/v7/go/aws/iam"
-	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/rs/zerolog/log"
 )
@@ -21,13 +21,13 @@ import (
 // ctx: Pulumi context for resource management.
 // account: The repository account configuration.
 // identityProviderArn: ARN of the AWS IAM Identity Provider for GitHub OIDC.
-// vaultStore: Vault mount point for storing secrets.
+// sink: The credential sink of the repository.
 // repositoriesConfig: Configuration for the GitHub repositories.
 // provider: AWS provider configured for the specific account.
 func createAccountIAM(ctx *pulumi.Context,
 	account *awsModel.RepositoryAccount,
-	identityProviderArn string,
-	vaultStore *vault.Mount,
+	identityProviderArn pulumi.StringOutput,
+	sink integration.Sink,
 	repositoriesConfig *repositories.Config,
 	provider *aws.Provider,
 ) (*iam.Role, error) {
@@ -41,8 +41,9 @@ func createAccountIAM(ctx *pulumi.Context,
 		ctx,
 		fmt.Sprintf("random-string-aws-iam-role-ci-%s-%s", *account.Repository, *account.ID),
 		&random.StringOptions{
-			Length:  postfixLength,
-			Special: false,
+			Length:        postfixLength,
+			Special:       false,
+			PulumiOptions: component.WithRepository(ctx, *account.Repository),
 		},
 	)
 	if ciPfErr != nil {
@@ -66,25 +67,17 @@ func createAccountIAM(ctx *pulumi.Context,
 		return nil, rErr
 	}
 
-	pulumi.All(vaultStore.Path, role.Arn).ApplyT(func(all []any) error {
-		path, _ := all[0].(string)
-		roleArn, _ := all[1].(string)
-
-		value, _ := json.Marshal(map[string]string{
-			"identity_role_arn": roleArn,
-			"region":            *account.Region,
-		})
-
-		_, err := secret.Create(ctx, &secret.CreateOptions{
-			Path:  path,
-			Key:   "aws",
-			Value: pulumi.String(value),
-			PulumiOptions: []pulumi.ResourceOption{
-				pulumi.Provider(config.VaultProvider),
-			},
-		})
-		return err
+	sErr := sink.Store(ctx, integration.Credential{
+		Key: "aws",
+		Values: pulumi.StringMap{
+			"identity_role_arn": role.Arn,
+			"region":            pulumi.String(*account.Region),
+		},
 	})
+	if sErr != nil {
+		log.Err(sErr).Msgf("[aws][iam] error storing AWS IAM role for repository: %s", *account.Repository)
+		return nil, sErr
+	}
 
 	return role, nil
 }
@@ -100,38 +93,41 @@ func createAccountIAM(ctx *pulumi.Context,
 // provider: AWS provider configured for the specific account.
 func createRole(ctx *pulumi.Context,
 	account *awsModel.RepositoryAccount,
-	identityProviderArn string,
+	identityProviderArn pulumi.StringOutput,
 	repositoriesConfig *repositories.Config,
 	tags map[string]string,
 	truncatedRepository string,
 	ciPostfix pulumi.StringOutput,
 	provider *aws.Provider,
 ) (*iam.Role, error) {
-	//nolint:gosec // false positive, this is not a hardcoded secret but a condition for the OIDC token
-	roleDoc, _ := json.Marshal(map[string]any{
-		"Version": "2012-10-17",
-		"Statement": []map[string]any{
-			{
-				"Effect": "Allow",
-				"Action": "sts:AssumeRoleWithWebIdentity",
-				"Principal": map[string]any{
-					"Federated": identityProviderArn,
-				},
-				"Condition": map[string]any{
-					"StringEquals": map[string]any{
-						"token.actions.githubusercontent.com:aud": "sts.amazonaws.com",
+	roleDoc := identityProviderArn.ApplyT(func(identityProviderArn string) (string, error) {
+		//nolint:gosec // false positive, this is not a hardcoded secret but a condition for the OIDC token
+		doc, err := json.Marshal(map[string]any{
+			"Version": "2012-10-17",
+			"Statement": []map[string]any{
+				{
+					"Effect": "Allow",
+					"Action": "sts:AssumeRoleWithWebIdentity",
+					"Principal": map[string]any{
+						"Federated": identityProviderArn,
 					},
-					"StringLike": map[string]any{
-						"token.actions.githubusercontent.com:sub": fmt.Sprintf(
-							"repo:%s/%s:*",
-							*repositoriesConfig.Owner,
-							*account.Repository,
-						),
+					"Condition": map[string]any{
+						"StringEquals": map[string]any{
+							"token.actions.githubusercontent.com:aud": "sts.amazonaws.com",
+						},
+						"StringLike": map[string]any{
+							"token.actions.githubusercontent.com:sub": fmt.Sprintf(
+								"repo:%s/%s:*",
+								*repositoriesConfig.Owner,
+								*account.Repository,
+							),
+						},
 					},
 				},
 			},
-		},
-	})
+		})
+		return string(doc), err
+	}).(pulumi.StringOutput)
 
 	//nolint:godox // TODO is required
 	// FIXME: move to shared library
@@ -141,10 +137,10 @@ func createRole(ctx *pulumi.Context,
 		&iam.RoleArgs{
 			Name:             pulumi.Sprintf("ci-%s-%s", truncatedRepository, ciPostfix),
 			Description:      pulumi.String(fmt.Sprintf("GitHub Repository: %s", *account.Repository)),
-			AssumeRolePolicy: pulumi.String(roleDoc),
+			AssumeRolePolicy: roleDoc,
 			Tags:             metadata.LabelsToStringMap(tags),
 		},
-		pulumi.Provider(provider),
+		component.WithRepository(ctx, *account.Repository, pulumi.Provider(provider))...,
 	)
 	if rErr != nil {
 		log.Err(rErr).Msgf("[aws][iam] error creating AWS IAM role for repository: %s", *account.Repository)
@@ -172,10 +168,14 @@ func createRole(ctx *pulumi.Context,
 			Policy:      pulumi.String(policyDoc),
 			Tags:        metadata.LabelsToStringMap(tags),
 		},
-		pulumi.Provider(provider),
-		pulumi.DependsOn([]pulumi.Resource{
-			role,
-		}),
+		component.WithRepository(
+			ctx,
+			*account.Repository,
+			pulumi.Provider(provider),
+			pulumi.DependsOn([]pulumi.Resource{
+				role,
+			}),
+		)...,
 	)
 	if pErr != nil {
 		log.Err(pErr).Msgf("[aws][iam] error creating AWS IAM policy for repository: %s", *account.Repository)
@@ -191,11 +191,15 @@ func createRole(ctx *pulumi.Context,
 			Role:      role.Name,
 			PolicyArn: policy.Arn,
 		},
-		pulumi.Provider(provider),
-		pulumi.DependsOn([]pulumi.Resource{
-			role,
-			policy,
-		}),
+		component.WithRepository(
+			ctx,
+			*account.Repository,
+			pulumi.Provider(provider),
+			pulumi.DependsOn([]pulumi.Resource{
+				role,
+				policy,
+			}),
+		)...,
 	)
 	if paErr != nil {
 		log.Err(paErr).Msgf("[aws][iam] error attaching AWS IAM policy to role for repository: %s", *account.Repository)
@@ -1,15 +1,16 @@
 package aws
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
 	awsModel "github.com/muhlba91/github-infrastructure/pkg/model/aws"
 	awsConf "github.com/muhlba91/github-infrastructure/pkg/model/config/aws"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
 	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
 	"github.com/pulumi/pulumi-aws/sdk/v7/go/aws"
-	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/rs/zerolog/log"
 )
@@ -17,18 +18,26 @@ import (
 // Configure sets up AWS resources based on the provided configuration.
 // ctx: Pulumi context for resource management.
 // repositories: List of repository configurations.
-// vaultStores: Map of Vault mount configurations.
+// sinks: Map of credential sinks keyed by repository name.
 // awsConfig: AWS configuration details.
 // repositoriesConfig: Repository configuration details.
 func Configure(ctx *pulumi.Context,
 	repositories []*repoConf.Config,
-	vaultStores map[string]*vault.Mount,
+	sinks map[string]integration.Sink,
 	awsConfig *awsConf.Config,
 	repositoriesConfig *repositories.Config,
-) (map[string][]string, error) {
-	providers := createProviders(ctx, awsConfig)
+) (map[string][]string, integration.Inventory, error) {
+	awsRepositoryAccounts, raErr := ResolveRepositoryAccounts(
+		repositories,
+		awsConfig,
+		defaults.GetOrDefault(repositoriesConfig.StrictReferences, false),
+	)
+	if raErr != nil {
+		log.Err(raErr).Msg("[aws][configure] error resolving AWS accounts for repositories")
+		return nil, nil, raErr
+	}
 
-	awsRepositoryAccounts := createAWSRepositoryAccounts(repositories, awsConfig)
+	providers := createProviders(ctx, awsConfig)
 
 	identityProviderArns, ipErr := ConfigureIdentityProviders(
 		ctx,
@@ -37,19 +46,26 @@ func Configure(ctx *pulumi.Context,
 	)
 	if ipErr != nil {
 		log.Err(ipErr).Msg("[aws][configure] error configuring AWS IAM Identity Providers")
-		return nil, ipErr
+		return nil, nil, ipErr
 	}
 
+	var errs []error
 	accounts := make(map[string][]string)
+	inventory := make(integration.Inventory)
 	for _, repositoryAccount := range awsRepositoryAccounts {
-		_ = configureAccount(
+		details, aErr := configureAccount(
 			ctx,
 			repositoryAccount,
 			identityProviderArns[*repositoryAccount.ID],
-			vaultStores[*repositoryAccount.Repository],
+			sinks[*repositoryAccount.Repository],
 			repositoriesConfig,
 			providers[*repositoryAccount.ID],
 		)
+		if aErr != nil {
+			errs = append(errs, fmt.Errorf("[aws][%s] %w", *repositoryAccount.Repository, aErr))
+			continue
+		}
+		inventory[*repositoryAccount.Repository] = details
 
 		accountRepositoryMapping, armOk := accounts[*repositoryAccount.ID]
 		if !armOk {
@@ -58,7 +74,11 @@ func Configure(ctx *pulumi.Context,
 		accounts[*repositoryAccount.ID] = append(accountRepositoryMapping, *repositoryAccount.Repository)
 	}
 
-	return accounts, nil
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	return accounts, inventory, nil
 }
 
 // createProviders initializes AWS providers for each account defined in the AWS configuration.
@@ -83,28 +103,32 @@ func createProviders(ctx *pulumi.Context, awsConfig *awsConf.Config) map[string]
 }
 
 // filterRepositoryByAllowedAccounts checks if the repository's specified AWS account is configured in the AWS settings.
+// It returns an error describing the unconfigured account.
 // repoAccessPermissionsAws: AWS access configuration for the repository.
 // awsConfig: AWS configuration details.
 func filterRepositoryByAllowedAccounts(
 	repoAccessPermissionsAws repoConf.AwsAccessConfig,
 	awsConfig *awsConf.Config,
-) bool {
-	mainAccount := repoAccessPermissionsAws.Account
-	if mainAccount == nil || awsConfig.Account[*mainAccount] == nil {
-		log.Error().Msgf("[aws][%v] the repository references an unconfigured account", *mainAccount)
-		return false
+) error {
+	mainAccount := defaults.GetOrDefault(repoAccessPermissionsAws.Account, "")
+	if awsConfig.Account[mainAccount] == nil {
+		return fmt.Errorf("the repository references an unconfigured account: %s", mainAccount)
 	}
 
-	return true
+	return nil
 }
 
-// createAWSRepositoryAccounts constructs a mapping of repository names to their corresponding AWS account configurations.
+// ResolveRepositoryAccounts constructs a mapping of repository names to their corresponding AWS account configurations.
+// Repositories referencing unconfigured accounts are skipped, or cause an error in strict mode.
 // repositories: List of repository configurations.
 // awsConfig: AWS configuration details.
-func createAWSRepositoryAccounts(
+// strict: Whether to return an error for repositories referencing unconfigured accounts.
+func ResolveRepositoryAccounts(
 	repositories []*repoConf.Config,
 	awsConfig *awsConf.Config,
-) map[string]*awsModel.RepositoryAccount {
+	strict bool,
+) (map[string]*awsModel.RepositoryAccount, error) {
+	var errs []error
 	awsRepositoryAccounts := make(map[string]*awsModel.RepositoryAccount)
 	for _, repository := range repositories {
 		repoAccessPermissions := defaults.GetOrDefault(
@@ -116,8 +140,16 @@ func createAWSRepositoryAccounts(
 			repoConf.AwsAccessConfig{},
 		)
 
-		if repoAccessPermissionsAws.Account != nil && *repoAccessPermissionsAws.Account != "" &&
-			filterRepositoryByAllowedAccounts(repoAccessPermissionsAws, awsConfig) {
+		if repoAccessPermissionsAws.Account != nil && *repoAccessPermissionsAws.Account != "" {
+			if fErr := filterRepositoryByAllowedAccounts(repoAccessPermissionsAws, awsConfig); fErr != nil {
+				if strict {
+					errs = append(errs, fmt.Errorf("[aws][%s] %w", repository.Name, fErr))
+				} else {
+					log.Error().Msgf("[aws][%s] %s", repository.Name, fErr)
+				}
+				continue
+			}
+
 			account := defaults.GetOrDefault(repoAccessPermissionsAws.Account, "")
 			region := defaults.GetOrDefault(
 				repoAccessPermissionsAws.Region,
@@ -136,5 +168,9 @@ func createAWSRepositoryAccounts(
 		}
 	}
 
-	return awsRepositoryAccounts
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return awsRepositoryAccounts, nil
 }
@@ -0,0 +1,85 @@
+package aws
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
+	awsConf "github.com/muhlba91/github-infrastructure/pkg/model/config/aws"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// integrationName is the name of the AWS integration.
+const integrationName = "aws"
+
+// Integration configures AWS IAM roles for repositories.
+type Integration struct {
+	// awsConfig contains the AWS configuration.
+	awsConfig *awsConf.Config
+	// repositoriesConfig contains the repositories configuration.
+	repositoriesConfig *repositories.Config
+}
+
+// NewIntegration creates the AWS integration.
+// awsConfig: AWS configuration details.
+// repositoriesConfig: Repository configuration details.
+func NewIntegration(awsConfig *awsConf.Config, repositoriesConfig *repositories.Config) integration.Integration {
+	return &Integration{
+		awsConfig:          awsConfig,
+		repositoriesConfig: repositoriesConfig,
+	}
+}
+
+// Name returns the name of the integration.
+func (*Integration) Name() string {
+	return integrationName
+}
+
+// Enabled returns whether the given repository references an AWS account.
+// repository: The repository configuration.
+func (*Integration) Enabled(repository *repoConf.Config) bool {
+	aws := accessConfig(repository)
+	return defaults.GetOrDefault(aws.Account, "") != ""
+}
+
+// Configure sets up AWS resources for the given repositories.
+// ctx: The Pulumi context for resource management.
+// repositories: The repositories the integration is enabled for.
+// sinks: The credential sinks keyed by repository name.
+func (i *Integration) Configure(
+	ctx *pulumi.Context,
+	repositories []*repoConf.Config,
+	sinks map[string]integration.Sink,
+) (any, integration.Inventory, error) {
+	return Configure(ctx, repositories, sinks, i.awsConfig, i.repositoriesConfig)
+}
+
+// Outputs returns the allowed AWS accounts and the repositories configured for each account.
+// configured: The repositories keyed by account returned by Configure.
+func (i *Integration) Outputs(configured any) map[string]any {
+	return map[string]any{
+		"allowed":    slices.Sorted(maps.Keys(i.awsConfig.Account)),
+		"configured": configured,
+	}
+}
+
+// Flags returns whether AWS access is configured for the given repository.
+// repository: The repository configuration.
+func (*Integration) Flags(repository *repoConf.Config) map[string]bool {
+	return map[string]bool{
+		integrationName: accessConfig(repository).Account != nil,
+	}
+}
+
+// accessConfig returns the AWS access configuration of the given repository.
+// repository: The repository configuration.
+func accessConfig(repository *repoConf.Config) repoConf.AwsAccessConfig {
+	repoAccessPermissions := defaults.GetOrDefault(
+		repository.AccessPermissions,
+		repoConf.AccessPermissionsConfig{},
+	)
+	return defaults.GetOrDefault(repoAccessPermissions.Aws, repoConf.AwsAccessConfig{})
+}
@@ -0,0 +1,80 @@
+//nolint:gochecknoglobals // the registered components are shared across all packages creating repository resources
+package component
+
+import (
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/rs/zerolog/log"
+)
+
+// repositoryType is the Pulumi type token of the repository component resource.
+const repositoryType = "muehlbachler:github:Repository"
+
+var (
+	// registries holds the registered repository components keyed by the Pulumi context of the run
+	// and the repository name.
+	registries = make(map[*pulumi.Context]map[string]*Repository)
+	// registriesMu guards registries.
+	registriesMu sync.Mutex
+)
+
+// Repository is a component resource grouping all resources managed for a single repository.
+type Repository struct {
+	pulumi.ResourceState
+}
+
+// NewRepository creates and registers the component resource of the given repository for the run of the given context.
+// ctx: The Pulumi context.
+// name: The name of the repository.
+func NewRepository(ctx *pulumi.Context, name string) (*Repository, error) {
+	component := &Repository{}
+	err := ctx.RegisterComponentResource(repositoryType, name, component)
+	if err != nil {
+		log.Err(err).Msgf("[component][repository] error creating component resource for repository: %s", name)
+		return nil, err
+	}
+
+	rErr := ctx.RegisterResourceOutputs(component, pulumi.Map{})
+	if rErr != nil {
+		log.Err(rErr).Msgf("[component][repository] error registering outputs for repository: %s", name)
+		return nil, rErr
+	}
+
+	registriesMu.Lock()
+	defer registriesMu.Unlock()
+	if _, ok := registries[ctx]; !ok {
+		registries[ctx] = make(map[string]*Repository)
+	}
+	registries[ctx][name] = component
+	return component, nil
+}
+
+// WithRepository returns the given resource options extended by the options parenting a resource
+// to the component of the given repository.
+// An alias to the unparented resource keeps the state of resources created before the component was introduced.
+// If no component is registered for the repository in the run of the given context, the options are returned unchanged.
+// ctx: The Pulumi context.
+// name: The name of the repository.
+// opts: The resource options of the resource.
+func WithRepository(ctx *pulumi.Context, name string, opts ...pulumi.ResourceOption) []pulumi.ResourceOption {
+	registriesMu.Lock()
+	component, ok := registries[ctx][name]
+	registriesMu.Unlock()
+	if !ok {
+		return opts
+	}
+
+	return append(opts,
+		pulumi.Parent(component),
+		pulumi.Aliases([]pulumi.Alias{{NoParent: pulumi.Bool(true)}}),
+	)
+}
+
+// Reset removes all components registered for the run of the given context.
+// ctx: The Pulumi context.
+func Reset(ctx *pulumi.Context) {
+	registriesMu.Lock()
+	defer registriesMu.Unlock()
+	delete(registries, ctx)
+}
@@ -10,13 +10,14 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
 	"github.com/rs/zerolog/log"
 
+	"github.com/muhlba91/github-infrastructure/pkg/lib/guardrail"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/aws"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/google"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/scaleway"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/stack"
 	vaultConf "github.com/muhlba91/github-infrastructure/pkg/model/config/vault"
-	vaultData "github.com/muhlba91/github-infrastructure/pkg/model/vault"
 	"github.com/muhlba91/github-infrastructure/pkg/util"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
 )
@@ -33,10 +34,8 @@ var (
 	AllowRepositoryDeletion = false
 	// IgnoreUnmanagedRepositories indicates whether to ignore unmanaged repositories.
 	IgnoreUnmanagedRepositories = false
-	// HasVaultConnection indicates whether a Vault connection is configured.
-	HasVaultConnection pulumi.BoolOutput
-	// VaultConnectionConfig holds the Vault connection configuration.
-	VaultConnectionConfig *vaultData.Config
+	// VaultEnabled indicates whether the Vault integration is enabled.
+	VaultEnabled = false
 	// VaultProvider holds the Vault provider resource.
 	VaultProvider *vault.Provider
 )
@@ -76,38 +75,42 @@ func LoadConfig(
 	unmanagedEnv := strings.ToLower(os.Getenv("IGNORE_UNMANAGED_REPOSITORIES"))
 	IgnoreUnmanagedRepositories = defaults.GetOrDefault(&unmanagedEnv, "false") == "true"
 
-	coreStack, sErr := pulumi.NewStackReference(
-		ctx,
-		fmt.Sprintf("%s/%s/%s", ctx.Organization(), "muehlbachler-core-infrastructure", Environment),
-		nil,
-	)
-	if sErr != nil {
-		log.Err(sErr).Msg("[config] error referencing core infrastructure stack for vault configuration")
-		return nil, nil, nil, nil, nil, nil, sErr
-	}
-	cStackVault := coreStack.GetOutput(pulumi.String("vault"))
-	HasVaultConnection = cStackVault.ApplyT(func(vaultConn any) bool { //nolint:errcheck // no error possible
-		vKeys, _ := vaultConn.(map[string]any)["keys"].(map[string]any)
-		vToken, _ := vKeys["rootToken"].(string)
-		VaultConnectionConfig = &vaultData.Config{
-			Address: vaultConfig.Address,
-			Token:   &vToken,
+	VaultEnabled = defaults.GetOrDefault(vaultConfig.Enabled, false)
+	if VaultEnabled {
+		var vErr error
+		VaultProvider, vErr = createVaultProvider(ctx, &vaultConfig)
+		if vErr != nil {
+			log.Err(vErr).Msg("[config] error creating vault provider")
+			return nil, nil, nil, nil, nil, nil, vErr
 		}
+	}
 
-		VaultProvider, _ = vault.NewProvider(ctx, "vault", &vault.ProviderArgs{
-			Address: pulumi.ToSecret(pulumi.StringPtr(*VaultConnectionConfig.Address)).(pulumi.StringPtrOutput),
-			Token:   pulumi.ToSecret(pulumi.StringPtr(*VaultConnectionConfig.Token)).(pulumi.StringPtrOutput),
-		})
-
-		return *vaultConfig.Enabled && VaultConnectionConfig.Token != nil && *VaultConnectionConfig.Token != ""
-	}).(pulumi.BoolOutput)
-
-	repos, rErr := util.ParseRepositoriesFromFiles("./assets/repositories")
+	repos, rErr := util.ParseRepositoriesFromFiles(
+		"./assets/repositories",
+		"./assets/templates/profiles",
+		repositoriesConfig.Defaults,
+	)
 	if rErr != nil {
 		log.Err(rErr).Msg("[config] error parsing repository configurations from files")
 		return nil, nil, nil, nil, nil, nil, rErr
 	}
 
+	guardrails, gErr := guardrail.Load("./assets/guardrails.yml")
+	if gErr != nil {
+		log.Err(gErr).Msg("[config] error loading guardrail rules")
+		return nil, nil, nil, nil, nil, nil, gErr
+	}
+	if cErr := guardrail.Check(guardrails, repos, &stack.Config{
+		Repositories: &repositoriesConfig,
+		Aws:          &awsConfig,
+		Google:       &gcpConfig,
+		Scaleway:     &scalewayConfig,
+		Vault:        &vaultConfig,
+	}); cErr != nil {
+		log.Err(cErr).Msg("[config] repository configurations violate guardrail rules")
+		return nil, nil, nil, nil, nil, nil, cErr
+	}
+
 	return &repositoriesConfig, &awsConfig, &gcpConfig, &scalewayConfig, &vaultConfig, repos, nil
 }
 
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/rs/zerolog/log"
+
+	vaultConf "github.com/muhlba91/github-infrastructure/pkg/model/config/vault"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+)
+
+// The Vault authentication methods.
+const (
+	// vaultAuthStackReference reads the token from the outputs of a stack.
+	vaultAuthStackReference = "stackReference"
+	// vaultAuthToken reads the token from an environment variable or a file.
+	vaultAuthToken = "token"
+	// vaultAuthAppRole logs in via the AppRole authentication method.
+	vaultAuthAppRole = "appRole"
+	// vaultAuthJWT logs in via the JWT authentication method.
+	vaultAuthJWT = "jwt"
+)
+
+const (
+	// defaultVaultTokenProject is the project of the stack containing the Vault token by default.
+	defaultVaultTokenProject = "muehlbachler-core-infrastructure"
+	// defaultVaultTokenOutputPath is the path of the Vault token in the stack's outputs by default.
+	defaultVaultTokenOutputPath = "vault.keys.rootToken"
+	// defaultVaultTokenEnv is the environment variable containing the Vault token by default.
+	defaultVaultTokenEnv = "VAULT_TOKEN"
+	// defaultVaultSecretIDEnv is the environment variable containing the AppRole secret ID by default.
+	defaultVaultSecretIDEnv = "VAULT_SECRET_ID"
+	// defaultVaultJWTEnv is the environment variable containing the JWT by default.
+	defaultVaultJWTEnv = "VAULT_JWT"
+)
+
+// createVaultProvider creates the Vault provider authenticating with the configured method.
+// ctx: The Pulumi context.
+// vaultConfig: The Vault configuration.
+func createVaultProvider(ctx *pulumi.Context, vaultConfig *vaultConf.Config) (*vault.Provider, error) {
+	auth := defaults.GetOrDefault(vaultConfig.Auth, vaultConf.AuthConfig{})
+	args := &vault.ProviderArgs{
+		Address: pulumi.ToSecret(pulumi.StringPtr(*vaultConfig.Address)).(pulumi.StringPtrOutput),
+	}
+
+	method := defaults.GetOrDefault(auth.Method, vaultAuthStackReference)
+	switch method {
+	case vaultAuthStackReference:
+		token, tErr := stackReferenceToken(ctx, defaults.GetOrDefault(
+			auth.StackReference,
+			vaultConf.StackReferenceAuthConfig{},
+		))
+		if tErr != nil {
+			return nil, tErr
+		}
+		args.Token = pulumi.ToSecret(token.ToStringPtrOutput()).(pulumi.StringPtrOutput)
+	case vaultAuthToken:
+		token, tErr := readSecret(auth.Token, defaultVaultTokenEnv)
+		if tErr != nil {
+			return nil, fmt.Errorf("vault token: %w", tErr)
+		}
+		args.Token = pulumi.ToSecret(pulumi.StringPtr(token)).(pulumi.StringPtrOutput)
+	case vaultAuthAppRole:
+		appRole := defaults.GetOrDefault(auth.AppRole, vaultConf.AppRoleAuthConfig{})
+		if defaults.GetOrDefault(appRole.RoleID, "") == "" {
+			return nil, errors.New("vault AppRole authentication requires a role ID")
+		}
+		secretID, sErr := readSecret(appRole.SecretID, defaultVaultSecretIDEnv)
+		if sErr != nil {
+			return nil, fmt.Errorf("vault AppRole secret ID: %w", sErr)
+		}
+		args.AuthLogin = &vault.ProviderAuthLoginArgs{
+			Path: pulumi.String(fmt.Sprintf("auth/%s/login", defaults.GetOrDefault(appRole.Mount, "approle"))),
+			Parameters: pulumi.ToSecret(pulumi.StringMap{
+				"role_id":   pulumi.String(*appRole.RoleID),
+				"secret_id": pulumi.String(secretID),
+			}).(pulumi.StringMapOutput),
+		}
+	case vaultAuthJWT:
+		jwt := defaults.GetOrDefault(auth.JWT, vaultConf.JWTAuthConfig{})
+		if defaults.GetOrDefault(jwt.Role, "") == "" {
+			return nil, errors.New("vault JWT authentication requires a role")
+		}
+		token, tErr := readSecret(jwt.Token, defaultVaultJWTEnv)
+		if tErr != nil {
+			return nil, fmt.Errorf("vault JWT: %w", tErr)
+		}
+		args.AuthLoginJwt = &vault.ProviderAuthLoginJwtArgs{
+			Mount: pulumi.StringPtr(defaults.GetOrDefault(jwt.Mount, "jwt")),
+			Role:  pulumi.String(*jwt.Role),
+			Jwt:   pulumi.ToSecret(pulumi.String(token)).(pulumi.StringOutput),
+		}
+	default:
+		return nil, fmt.Errorf("unsupported Vault authentication method: %s", method)
+	}
+
+	provider, pErr := vault.NewProvider(ctx, "vault", args)
+	if pErr != nil {
+		log.Err(pErr).Msgf("[config][vault] error creating vault provider with authentication method: %s", method)
+		return nil, pErr
+	}
+
+	return provider, nil
+}
+
+// stackReferenceToken reads the Vault token from the outputs of the configured stack.
+// ctx: The Pulumi context.
+// stackReference: The stack reference configuration.
+func stackReferenceToken(
+	ctx *pulumi.Context,
+	stackReference vaultConf.StackReferenceAuthConfig,
+) (pulumi.StringOutput, error) {
+	name := defaults.GetOrDefault(
+		stackReference.Name,
+		fmt.Sprintf("%s/%s/%s", ctx.Organization(), defaultVaultTokenProject, Environment),
+	)
+	outputPath := defaults.GetOrDefault(stackReference.OutputPath, defaultVaultTokenOutputPath)
+
+	stack, sErr := pulumi.NewStackReference(ctx, name, nil)
+	if sErr != nil {
+		log.Err(sErr).Msgf("[config][vault] error referencing stack for vault token: %s", name)
+		return pulumi.StringOutput{}, sErr
+	}
+
+	path := strings.Split(outputPath, ".")
+	token, _ := stack.GetOutput(pulumi.String(path[0])).ApplyT(func(output any) (string, error) {
+		for _, segment := range path[1:] {
+			values, _ := output.(map[string]any)
+			output = values[segment]
+		}
+		token, _ := output.(string)
+		if token == "" {
+			return "", fmt.Errorf("no Vault token found at '%s' in the outputs of stack: %s", outputPath, name)
+		}
+		return token, nil
+	}).(pulumi.StringOutput)
+
+	return token, nil
+}
+
+// readSecret reads a secret from the configured file, or the configured environment variable.
+// source: The source of the secret; may be nil.
+// defaultEnv: The environment variable used if no source is configured.
+func readSecret(source *vaultConf.SecretSourceConfig, defaultEnv string) (string, error) {
+	src := defaults.GetOrDefault(source, vaultConf.SecretSourceConfig{})
+
+	if file := defaults.GetOrDefault(src.File, ""); file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		secret := strings.TrimSpace(string(b))
+		if secret == "" {
+			return "", fmt.Errorf("file is empty: %s", file)
+		}
+		return secret, nil
+	}
+
+	env := defaults.GetOrDefault(src.Env, defaultEnv)
+	secret := os.Getenv(env)
+	if secret == "" {
+		return "", fmt.Errorf("environment variable is not set: %s", env)
+	}
+	return secret, nil
+}
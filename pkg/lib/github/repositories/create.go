@@ -3,15 +3,18 @@ package repositories
 import (
 	"fmt"
 
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
 	"github.com/muhlba91/github-infrastructure/pkg/lib/config"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
 	ghUtils "github.com/muhlba91/github-infrastructure/pkg/util/github"
+	"github.com/pulumi/pulumi-command/sdk/go/command/local"
 	"github.com/pulumi/pulumi-github/sdk/v6/go/github"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/rs/zerolog/log"
 
 	libRepo "github.com/muhlba91/pulumi-shared-library/pkg/lib/github/repository"
+	libRuleset "github.com/muhlba91/pulumi-shared-library/pkg/lib/github/ruleset"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
 )
 
@@ -19,6 +22,7 @@ import (
 const defaultVisibility = "public"
 
 // Create creates multiple GitHub repositories based on the provided configuration.
+// It returns the created repositories and the projects linked to them, both keyed by repository name.
 // ctx: The Pulumi context for resource creation.
 // repositories: A slice of repository configurations to create.
 // repositoriesConfig: General configuration for repositories.
@@ -26,19 +30,35 @@ func Create(
 	ctx *pulumi.Context,
 	repositories []*repository.Config,
 	repositoriesConfig *repositories.Config,
-) (map[string]*github.Repository, error) {
+) (map[string]*github.Repository, map[string]*local.Command, error) {
 	repos := make(map[string]*github.Repository)
+	projects := make(map[string]*local.Command)
 
 	for _, repo := range repositories {
 		ghRepo, err := create(ctx, repo, repositoriesConfig)
 		if err != nil {
 			log.Err(err).Msgf("[github][repository] error creating GitHub repository: %s", repo.Name)
-			return nil, err
+			return nil, nil, err
 		}
 		repos[repo.Name] = ghRepo
+
+		if defaults.GetOrDefault(repo.CreateProject, false) {
+			project, pErr := createProject(
+				ctx,
+				fmt.Sprintf("%s-%s", *repositoriesConfig.Owner, repo.Name),
+				*repositoriesConfig.Owner,
+				repo,
+				ghRepo,
+			)
+			if pErr != nil {
+				log.Err(pErr).Msgf("[github][repository] error creating project for repository: %s", repo.Name)
+				return nil, nil, pErr
+			}
+			projects[repo.Name] = project
+		}
 	}
 
-	return repos, nil
+	return repos, projects, nil
 }
 
 // create creates a single GitHub repository based on the provided configuration.
@@ -55,19 +75,23 @@ func create(
 	owner := repositoriesConfig.Owner
 	resourceName := fmt.Sprintf("%s-%s", *owner, repository.Name)
 
+	var opts []pulumi.ResourceOption
 	if !manageLifecycle && !config.IgnoreUnmanagedRepositories {
-		config.Stack.GetOutput(pulumi.String("repositories")).ApplyT(func(repos any) error {
-			repoMap, _ := repos.(map[string]any)
-			if _, exists := repoMap[repository.Name]; !exists {
-				return fmt.Errorf(
-					"[ERROR] repository '%s' is not imported yet! Please import it using the following command and re-run Pulumi with IGNORE_UNMANAGED_REPOSITORIES=\"true\": pulumi import github:index/repository:Repository %s %s",
-					repository.Name,
-					resourceName,
-					repository.Name,
-				)
-			}
-			return nil
-		})
+		// the repository depends on the check, hence the registration of a repository which is not imported yet fails
+		imported := config.Stack.GetOutput(pulumi.String("repositories")).
+			ApplyT(func(repos any) ([]pulumi.Resource, error) {
+				repoMap, _ := repos.(map[string]any)
+				if _, exists := repoMap[repository.Name]; !exists {
+					return nil, fmt.Errorf(
+						"[ERROR] repository '%s' is not imported yet! Please import it using the following command and re-run Pulumi with IGNORE_UNMANAGED_REPOSITORIES=\"true\": pulumi import github:index/repository:Repository %s %s",
+						repository.Name,
+						resourceName,
+						repository.Name,
+					)
+				}
+				return nil, nil
+			}).(pulumi.ResourceArrayOutput)
+		opts = append(opts, pulumi.DependsOnInputs(imported))
 	}
 
 	defVis := defaultVisibility
@@ -82,6 +106,7 @@ func create(
 		Visibility:              defaults.GetOrDefault(&repository.Visibility, &defVis),
 		Protected:               defaults.GetOrDefault(repository.Protected, false),
 		AllowRepositoryDeletion: manageLifecycle || !config.AllowRepositoryDeletion,
+		PulumiOptions:           component.WithRepository(ctx, repository.Name, opts...),
 	})
 	if err != nil {
 		log.Err(err).
@@ -89,15 +114,82 @@ func create(
 		return nil, err
 	}
 
-	if (ghUtils.HasSubscription(repositoriesConfig.Subscription) || !ghUtils.IsPrivate(repository.Visibility)) &&
-		repository.Rulesets.Branch.Enabled {
-		rsErr := createRuleset(ctx, fmt.Sprintf("branch-%s-%s", *owner, repository.Name), repository, repo)
+	if ghUtils.HasSubscription(repositoriesConfig.Subscription) || !ghUtils.IsPrivate(repository.Visibility) {
+		rsErr := createRulesets(ctx, *owner, repository, repo)
 		if rsErr != nil {
-			log.Err(rsErr).
-				Msgf("[github][repository] error creating branch ruleset for repository: %s", repository.Name)
+			log.Err(rsErr).Msgf("[github][repository] error creating rulesets for repository: %s", repository.Name)
 			return nil, rsErr
 		}
 	}
 
 	return repo, nil
 }
+
+// createRulesets creates the branch, tag, and custom rulesets for the given repository.
+// ctx: The Pulumi context for resource creation.
+// owner: The owner of the repository.
+// repository: The configuration for the repository.
+// repo: The Pulumi GitHub repository resource.
+func createRulesets(
+	ctx *pulumi.Context,
+	owner string,
+	repository *repository.Config,
+	repo *github.Repository,
+) error {
+	if repository.Rulesets.Branch.Enabled {
+		_, rsErr := createRuleset(
+			ctx,
+			fmt.Sprintf("branch-%s-%s", owner, repository.Name),
+			repository.Name,
+			repository.Rulesets.Branch,
+			rulesetTargetBranch,
+			[]string{libRuleset.DefaultBranchRulesetPattern},
+			repo,
+		)
+		if rsErr != nil {
+			log.Err(rsErr).
+				Msgf("[github][repository] error creating branch ruleset for repository: %s", repository.Name)
+			return rsErr
+		}
+	}
+
+	if repository.Rulesets.Tag != nil && repository.Rulesets.Tag.Enabled {
+		_, rsErr := createRuleset(
+			ctx,
+			fmt.Sprintf("tag-%s-%s", owner, repository.Name),
+			repository.Name,
+			repository.Rulesets.Tag,
+			rulesetTargetTag,
+			[]string{libRuleset.DefaultTagRulesetPattern},
+			repo,
+		)
+		if rsErr != nil {
+			log.Err(rsErr).
+				Msgf("[github][repository] error creating tag ruleset for repository: %s", repository.Name)
+			return rsErr
+		}
+	}
+
+	for _, ruleset := range repository.Rulesets.Custom {
+		if !ruleset.Enabled {
+			continue
+		}
+
+		_, rsErr := createRuleset(
+			ctx,
+			fmt.Sprintf("ruleset-%s-%s-%s", ruleset.Name, owner, repository.Name),
+			repository.Name,
+			ruleset,
+			rulesetTargetBranch,
+			[]string{},
+			repo,
+		)
+		if rsErr != nil {
+			log.Err(rsErr).
+				Msgf("[github][repository] error creating ruleset %s for repository: %s", ruleset.Name, repository.Name)
+			return rsErr
+		}
+	}
+
+	return nil
+}
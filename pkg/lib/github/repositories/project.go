@@ -0,0 +1,138 @@
+//nolint:gochecknoglobals // globals are allowed in this file
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	"github.com/pulumi/pulumi-command/sdk/go/command/local"
+	"github.com/pulumi/pulumi-github/sdk/v6/go/github"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// projectScript is the script creating or updating a project, and printing its ID.
+	projectScript = "./assets/github/project.sh"
+	// deleteProjectScript deletes the project whose ID was printed by the project script.
+	deleteProjectScript = `gh api graphql ` +
+		`-f query='mutation($project: ID!) { deleteProjectV2(input: {projectId: $project}) { projectV2 { id } } }' ` +
+		`-f project="${PULUMI_COMMAND_STDOUT}"`
+	// projectOptionColor is the color of the options of single select fields.
+	projectOptionColor = "GRAY"
+)
+
+// defaultProjectColumns defines the default status columns of a repository project.
+var defaultProjectColumns = []string{
+	"Todo",
+	"In Progress",
+	"Done",
+}
+
+// projectFieldTypes maps the configured types of project fields to their GitHub data types.
+var projectFieldTypes = map[string]string{
+	"text":         "TEXT",
+	"number":       "NUMBER",
+	"date":         "DATE",
+	"singleSelect": "SINGLE_SELECT",
+}
+
+// projectOption is an option of a single select field of a project.
+type projectOption struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// projectField is a custom field of a project.
+type projectField struct {
+	Name                string          `json:"name"`
+	DataType            string          `json:"dataType"`
+	SingleSelectOptions []projectOption `json:"singleSelectOptions,omitempty"`
+}
+
+// createProject creates a GitHub project (v2) linked to the given repository.
+// GitHub projects cannot be managed by the GitHub provider, hence the project is managed via the GitHub GraphQL API
+// by a command, which requires the GitHub CLI and jq, and prints the ID of the project.
+// ctx: The Pulumi context for resource creation.
+// name: The name of the project resource.
+// owner: The owner of the repository.
+// repositoryConfig: The configuration for the repository.
+// repo: The Pulumi GitHub repository resource.
+func createProject(
+	ctx *pulumi.Context,
+	name string,
+	owner string,
+	repositoryConfig *repository.Config,
+	repo *github.Repository,
+) (*local.Command, error) {
+	projectConfig := defaults.GetOrDefault(repositoryConfig.Project, repository.ProjectConfig{})
+
+	script, err := os.ReadFile(projectScript)
+	if err != nil {
+		log.Err(err).Msgf("[github][project] error reading project script for repository: %s", repositoryConfig.Name)
+		return nil, err
+	}
+
+	columns := projectConfig.Columns
+	if len(columns) == 0 {
+		columns = defaultProjectColumns
+	}
+	statusOptions, sErr := json.Marshal(projectOptions(columns))
+	if sErr != nil {
+		return nil, sErr
+	}
+
+	fields := []projectField{}
+	for _, field := range projectConfig.Fields {
+		dataType, ok := projectFieldTypes[field.Type]
+		if !ok {
+			return nil, fmt.Errorf("unsupported type '%s' of project field '%s' for repository: %s",
+				field.Type, field.Name, repositoryConfig.Name)
+		}
+		fields = append(fields, projectField{
+			Name:                field.Name,
+			DataType:            dataType,
+			SingleSelectOptions: projectOptions(field.Options),
+		})
+	}
+	projectFields, fErr := json.Marshal(fields)
+	if fErr != nil {
+		return nil, fErr
+	}
+
+	project, pErr := local.NewCommand(ctx, fmt.Sprintf("project-%s", name), &local.CommandArgs{
+		Interpreter: pulumi.ToStringArray([]string{"/bin/bash", "-c"}),
+		Create:      pulumi.String(string(script)),
+		Update:      pulumi.String(string(script)),
+		Delete:      pulumi.String(deleteProjectScript),
+		Environment: pulumi.StringMap{
+			"PROJECT_OWNER":          pulumi.String(owner),
+			"PROJECT_REPOSITORY_ID":  repo.NodeId,
+			"PROJECT_TITLE":          pulumi.String(defaults.GetOrDefault(projectConfig.Name, repositoryConfig.Name)),
+			"PROJECT_DESCRIPTION":    pulumi.String(defaults.GetOrDefault(projectConfig.Description, repositoryConfig.Description)),
+			"PROJECT_STATUS_OPTIONS": pulumi.String(string(statusOptions)),
+			"PROJECT_FIELDS":         pulumi.String(string(projectFields)),
+		},
+	}, component.WithRepository(ctx, repositoryConfig.Name, pulumi.DependsOn([]pulumi.Resource{repo}))...)
+	if pErr != nil {
+		log.Err(pErr).Msgf("[github][project] error creating project for repository: %s", repositoryConfig.Name)
+		return nil, pErr
+	}
+
+	return project, nil
+}
+
+// projectOptions returns the options of a single select field with the given names, in the given order.
+// names: The names of the options.
+func projectOptions(names []string) []projectOption {
+	options := []projectOption{}
+	for _, name := range names {
+		options = append(options, projectOption{Name: name, Color: projectOptionColor})
+	}
+	return options
+}
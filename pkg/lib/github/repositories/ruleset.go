@@ -1,44 +1,144 @@
 package repositories
 
 import (
+	"fmt"
+	"slices"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
 	"github.com/pulumi/pulumi-github/sdk/v6/go/github"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
 	libRuleset "github.com/muhlba91/pulumi-shared-library/pkg/lib/github/ruleset"
 )
 
-// createRuleset creates a branch ruleset for the given repository based on the provided configuration.
+const (
+	// rulesetTargetBranch is the ruleset target for branches.
+	rulesetTargetBranch = "branch"
+	// rulesetTargetTag is the ruleset target for tags.
+	rulesetTargetTag = "tag"
+	// rulesetTargetPush is the ruleset target for pushes.
+	rulesetTargetPush = "push"
+	// defaultRulesetEnforcement is the default enforcement mode of rulesets.
+	defaultRulesetEnforcement = "active"
+)
+
+//nolint:gochecknoglobals // lookup table of supported values
+var rulesetEnforcements = []string{defaultRulesetEnforcement, "evaluate", "disabled"}
+
+// createRuleset creates a ruleset for the given repository based on the provided configuration.
+// The options applied depend on the target of the ruleset: pull request related options are only applied to
+// branch rulesets, and push rulesets restrict the pushed files instead of refs.
+// All rulesets are created by the shared library, hence their names and bypass actors are the same for all targets.
 // ctx: The Pulumi context for resource creation.
 // name: The name of the ruleset.
-// repository: The configuration for the repository.
+// repositoryName: The name of the repository.
+// rulesetConfig: The configuration for the ruleset.
+// defaultTarget: The target to use if the configuration does not define one.
+// defaultPatterns: The patterns which are always included in the ruleset.
 // repo: The Pulumi GitHub repository resource.
 func createRuleset(
 	ctx *pulumi.Context,
 	name string,
-	repository *repository.Config,
+	repositoryName string,
+	rulesetConfig *repository.RulesetConfig,
+	defaultTarget string,
+	defaultPatterns []string,
 	repo *github.Repository,
-) error {
+) (*github.RepositoryRuleset, error) {
 	delOnDestroy := true
-	patterns := append([]string{libRuleset.DefaultBranchRulesetPattern}, repository.Rulesets.Branch.Patterns...)
-
-	_, err := libRuleset.Create(ctx, name, &libRuleset.CreateOptions{
-		Repository:               repo,
-		Patterns:                 patterns,
-		RestrictCreation:         repository.Rulesets.Branch.RestrictCreation,
-		AllowForcePush:           repository.Rulesets.Branch.AllowForcePush,
-		SignedCommits:            repository.Rulesets.Branch.RequireSignedCommits,
-		CodeOwnerReview:          repository.Rulesets.Branch.RequireCodeOwnerReview,
-		ConversationResolution:   repository.Rulesets.Branch.RequireConversationResolution,
-		LastPushApproval:         repository.Rulesets.Branch.RequireLastPushApproval,
-		ReviewerCount:            repository.Rulesets.Branch.ApprovingReviewCount,
-		EnableMergeQueue:         repository.Rulesets.Branch.EnableMergeQueue,
-		AllowBypass:              repository.Rulesets.Branch.AllowBypass,
-		AllowBypassIntegrations:  repository.Rulesets.Branch.AllowBypassIntegrations,
-		UpdatedBranchBeforeMerge: repository.Rulesets.Branch.RequireUpdatedBranchBeforeMerge,
-		RequiredChecks:           repository.Rulesets.Branch.RequiredChecks,
-		WIPIntegration:           repository.Rulesets.Branch.EnableWipIntegration,
-		DeleteOnDestroy:          &delOnDestroy,
-	})
-	return err
+	target := defaults.GetOrDefault(rulesetConfig.Target, defaultTarget)
+	enforcement := defaults.GetOrDefault(rulesetConfig.Enforcement, defaultRulesetEnforcement)
+	if !slices.Contains(rulesetEnforcements, enforcement) {
+		return nil, fmt.Errorf("unsupported enforcement '%s' for ruleset: %s", enforcement, name)
+	}
+
+	opts := &libRuleset.CreateOptions{
+		Repository:              repo,
+		Target:                  &target,
+		Enforcement:             &enforcement,
+		Patterns:                append(slices.Clone(defaultPatterns), rulesetConfig.Patterns...),
+		ExcludePatterns:         rulesetConfig.ExcludePatterns,
+		AllowBypass:             rulesetConfig.AllowBypass,
+		AllowBypassIntegrations: rulesetConfig.AllowBypassIntegrations,
+		DeleteOnDestroy:         &delOnDestroy,
+		PulumiOptions:           component.WithRepository(ctx, repositoryName),
+	}
+
+	switch target {
+	case rulesetTargetBranch:
+		opts.RestrictCreation = rulesetConfig.RestrictCreation
+		opts.AllowForcePush = rulesetConfig.AllowForcePush
+		opts.SignedCommits = rulesetConfig.RequireSignedCommits
+		opts.CodeOwnerReview = rulesetConfig.RequireCodeOwnerReview
+		opts.ConversationResolution = rulesetConfig.RequireConversationResolution
+		opts.LastPushApproval = rulesetConfig.RequireLastPushApproval
+		opts.ReviewerCount = rulesetConfig.ApprovingReviewCount
+		opts.EnableMergeQueue = rulesetConfig.EnableMergeQueue
+		opts.UpdatedBranchBeforeMerge = rulesetConfig.RequireUpdatedBranchBeforeMerge
+		opts.RequiredChecks = rulesetConfig.RequiredChecks
+		opts.WIPIntegration = rulesetConfig.EnableWipIntegration
+		opts.GitStreamIntegration = rulesetConfig.EnableGitstreamIntegration
+	case rulesetTargetTag:
+		opts.RestrictCreation = rulesetConfig.RestrictCreation
+		opts.AllowForcePush = rulesetConfig.AllowForcePush
+		opts.SignedCommits = rulesetConfig.RequireSignedCommits
+	case rulesetTargetPush:
+		rules, rErr := pushRules(name, rulesetConfig)
+		if rErr != nil {
+			return nil, rErr
+		}
+		opts.PulumiOptions = append(opts.PulumiOptions,
+			pulumi.Transformations([]pulumi.ResourceTransformation{withPushRules(rules)}),
+		)
+	default:
+		return nil, fmt.Errorf("unsupported target '%s' for ruleset: %s", target, name)
+	}
+
+	return libRuleset.Create(ctx, name, opts)
+}
+
+// pushRules returns the rules of a push ruleset based on the provided configuration.
+// Push rulesets apply to every push to the repository, hence they have no patterns but restrict the pushed files.
+// name: The name of the ruleset.
+// rulesetConfig: The configuration for the ruleset.
+func pushRules(name string, rulesetConfig *repository.RulesetConfig) (*github.RepositoryRulesetRulesArgs, error) {
+	rules := &github.RepositoryRulesetRulesArgs{}
+	if len(rulesetConfig.RestrictedFilePaths) > 0 {
+		rules.FilePathRestriction = &github.RepositoryRulesetRulesFilePathRestrictionArgs{
+			RestrictedFilePaths: pulumi.ToStringArray(rulesetConfig.RestrictedFilePaths),
+		}
+	}
+	if rulesetConfig.MaxFileSize != nil {
+		rules.MaxFileSize = &github.RepositoryRulesetRulesMaxFileSizeArgs{
+			MaxFileSize: pulumi.Int(*rulesetConfig.MaxFileSize),
+		}
+	}
+	if len(rulesetConfig.RestrictedFileExtensions) > 0 {
+		rules.FileExtensionRestriction = &github.RepositoryRulesetRulesFileExtensionRestrictionArgs{
+			RestrictedFileExtensions: pulumi.ToStringArray(rulesetConfig.RestrictedFileExtensions),
+		}
+	}
+	if rules.FilePathRestriction == nil && rules.MaxFileSize == nil && rules.FileExtensionRestriction == nil {
+		return nil, fmt.Errorf("push ruleset without any push rule: %s", name)
+	}
+
+	return rules, nil
+}
+
+// withPushRules returns a transformation replacing the ref based rules and conditions of a ruleset created by the
+// shared library with the given push rules, keeping its name, enforcement, and bypass actors.
+// rules: The push rules of the ruleset.
+func withPushRules(rules *github.RepositoryRulesetRulesArgs) pulumi.ResourceTransformation {
+	return func(args *pulumi.ResourceTransformationArgs) *pulumi.ResourceTransformationResult {
+		props, ok := args.Props.(*github.RepositoryRulesetArgs)
+		if !ok {
+			return nil
+		}
+
+		props.Rules = rules
+		props.Conditions = nil
+		return &pulumi.ResourceTransformationResult{Props: props, Opts: args.Opts}
+	}
 }
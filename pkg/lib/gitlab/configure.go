@@ -1,16 +1,16 @@
 package gitlab
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
 	"maps"
 	"slices"
 
-	"github.com/muhlba91/github-infrastructure/pkg/lib/config"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
 	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
 	"github.com/muhlba91/pulumi-shared-library/pkg/lib/gitlab/groupaccesstoken"
-	vaultSecret "github.com/muhlba91/pulumi-shared-library/pkg/lib/vault/secret"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
-	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/rs/zerolog/log"
 )
@@ -18,52 +18,59 @@ import (
 // Configure sets up GitLab configurations for the specified repositories.
 // ctx: The Pulumi context for resource management.
 // repositories: A slice of repository configurations.
-// vaultStores: A map of Vault mount configurations.
+// sinks: Map of credential sinks keyed by repository name.
 func Configure(
 	ctx *pulumi.Context,
 	repositories []*repoConf.Config,
-	vaultStores map[string]*vault.Mount,
-) ([]string, error) {
+	sinks map[string]integration.Sink,
+) ([]string, integration.Inventory, error) {
 	repos := filterRepositories(repositories)
 
+	var errs []error
+	inventory := make(integration.Inventory)
 	for name, repository := range repos {
 		token, tErr := groupaccesstoken.Create(
 			ctx,
 			name,
 			&groupaccesstoken.CreateOptions{
-				Name:        pulumi.String(name),
-				Description: pulumi.String(name),
-				Group:       repository.AccessPermissions.GitLab.Group,
-				Scopes:      repository.AccessPermissions.GitLab.Scopes,
+				Name:          pulumi.String(name),
+				Description:   pulumi.String(name),
+				Group:         repository.AccessPermissions.GitLab.Group,
+				Scopes:        repository.AccessPermissions.GitLab.Scopes,
+				PulumiOptions: component.WithRepository(ctx, name),
 			},
 		)
 		if tErr != nil {
 			log.Err(tErr).
 				Msgf("[gitlab][configure] error creating GitLab access token for repository: %s", repository.Name)
-			return nil, tErr
+			errs = append(errs, fmt.Errorf("[gitlab][%s] %w", repository.Name, tErr))
+			continue
 		}
 
-		pulumi.All(vaultStores[name].Path, token.Token).
-			ApplyT(func(all []any) error {
-				path, _ := all[0].(string)
-				token, _ := all[1].(string)
+		sErr := sinks[name].Store(ctx, integration.Credential{
+			Key: "gitlab",
+			Secrets: pulumi.StringMap{
+				"token": token.Token,
+			},
+		})
+		if sErr != nil {
+			log.Err(sErr).
+				Msgf("[gitlab][configure] error storing GitLab access token for repository: %s", repository.Name)
+			errs = append(errs, fmt.Errorf("[gitlab][%s] %w", repository.Name, sErr))
+			continue
+		}
+
+		inventory[name] = pulumi.Map{
+			"tokenName": token.Name,
+			"expiresAt": token.ExpiresAt,
+		}
+	}
 
-				value, _ := json.Marshal(map[string]string{
-					"token": token,
-				})
-				_, _ = vaultSecret.Create(ctx, &vaultSecret.CreateOptions{
-					Path:  path,
-					Key:   "gitlab",
-					Value: pulumi.String(value),
-					PulumiOptions: []pulumi.ResourceOption{
-						pulumi.Provider(config.VaultProvider),
-					},
-				})
-				return nil
-			})
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
 	}
 
-	return slices.Collect(maps.Keys(repos)), nil
+	return slices.Collect(maps.Keys(repos)), inventory, nil
 }
 
 // filterRepositories filters the given repositories to include only those that we want to create GitLab configurations for.
@@ -71,14 +78,20 @@ func Configure(
 func filterRepositories(repositories []*repoConf.Config) map[string]*repoConf.Config {
 	repos := make(map[string]*repoConf.Config)
 	for _, repository := range repositories {
-		repoAccessPermissions := defaults.GetOrDefault(
-			repository.AccessPermissions,
-			repoConf.AccessPermissionsConfig{},
-		)
-		if repoAccessPermissions.GitLab != nil && len(repoAccessPermissions.GitLab.Scopes) > 0 {
+		if enabled(repository) {
 			repos[repository.Name] = repository
 		}
 	}
 
 	return repos
 }
+
+// enabled returns whether GitLab access is configured for the given repository.
+// repository: The repository configuration.
+func enabled(repository *repoConf.Config) bool {
+	repoAccessPermissions := defaults.GetOrDefault(
+		repository.AccessPermissions,
+		repoConf.AccessPermissionsConfig{},
+	)
+	return repoAccessPermissions.GitLab != nil && len(repoAccessPermissions.GitLab.Scopes) > 0
+}
@@ -0,0 +1,57 @@
+package gitlab
+
+import (
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// integrationName is the name of the GitLab integration.
+const integrationName = "gitlab"
+
+// Integration configures GitLab group access tokens for repositories.
+type Integration struct{}
+
+// NewIntegration creates the GitLab integration.
+func NewIntegration() integration.Integration {
+	return &Integration{}
+}
+
+// Name returns the name of the integration.
+func (*Integration) Name() string {
+	return integrationName
+}
+
+// Enabled returns whether GitLab access is configured for the given repository.
+// repository: The repository configuration.
+func (*Integration) Enabled(repository *repoConf.Config) bool {
+	return enabled(repository)
+}
+
+// Configure creates GitLab group access tokens for the given repositories.
+// ctx: The Pulumi context for resource management.
+// repositories: The repositories the integration is enabled for.
+// sinks: The credential sinks keyed by repository name.
+func (*Integration) Configure(
+	ctx *pulumi.Context,
+	repositories []*repoConf.Config,
+	sinks map[string]integration.Sink,
+) (any, integration.Inventory, error) {
+	return Configure(ctx, repositories, sinks)
+}
+
+// Outputs returns the repositories GitLab access tokens are configured for.
+// configured: The configured repositories returned by Configure.
+func (*Integration) Outputs(configured any) map[string]any {
+	return map[string]any{
+		"tokens": configured,
+	}
+}
+
+// Flags returns whether GitLab access is configured for the given repository.
+// repository: The repository configuration.
+func (*Integration) Flags(repository *repoConf.Config) map[string]bool {
+	return map[string]bool{
+		integrationName: enabled(repository),
+	}
+}
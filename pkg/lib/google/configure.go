@@ -1,16 +1,18 @@
 package google
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"slices"
 
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
 	googleConf "github.com/muhlba91/github-infrastructure/pkg/model/config/google"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
 	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
 	"github.com/muhlba91/github-infrastructure/pkg/model/google"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
 	"github.com/pulumi/pulumi-gcp/sdk/v9/go/gcp"
-	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/rs/zerolog/log"
 )
@@ -18,23 +20,31 @@ import (
 // Configure sets up Google Cloud resources based on the provided configuration.
 // ctx: Pulumi context for resource management.
 // repositories: List of repository configurations.
-// vaultStores: Map of Vault mount configurations.
+// sinks: Map of credential sinks keyed by repository name.
 // gcpConfig: Google Cloud configuration details.
 // repositoriesConfig: Repository configuration details.
 func Configure(ctx *pulumi.Context,
 	repositories []*repoConf.Config,
-	vaultStores map[string]*vault.Mount,
+	sinks map[string]integration.Sink,
 	gcpConfig *googleConf.Config,
 	repositoriesConfig *repositories.Config,
-) (map[string][]string, error) {
-	providers := createProviders(ctx, gcpConfig)
+) (map[string][]string, integration.Inventory, error) {
+	googleRepositoryProjects, rpErr := ResolveRepositoryProjects(
+		repositories,
+		gcpConfig,
+		defaults.GetOrDefault(repositoriesConfig.StrictReferences, false),
+	)
+	if rpErr != nil {
+		log.Err(rpErr).Msg("[google][configure] error resolving Google Cloud projects for repositories")
+		return nil, nil, rpErr
+	}
 
-	googleRepositoryProjects := createGoogleRepositoryProjects(repositories, gcpConfig)
+	providers := createProviders(ctx, gcpConfig)
 
 	enabledServices, enableErr := EnableProjectServices(ctx, googleRepositoryProjects, gcpConfig, providers)
 	if enableErr != nil {
 		log.Err(enableErr).Msg("[google][configure] error enabling Google Cloud services for repository projects")
-		return nil, enableErr
+		return nil, nil, enableErr
 	}
 
 	workloadIdentities, wiErr := ConfigureWorkloadIdentityPools(
@@ -46,16 +56,18 @@ func Configure(ctx *pulumi.Context,
 	if wiErr != nil {
 		log.Err(wiErr).
 			Msg("[google][configure] error configuring Google Cloud Workload Identity Pools for repository projects")
-		return nil, wiErr
+		return nil, nil, wiErr
 	}
 
+	var errs []error
 	projects := make(map[string][]string)
+	inventory := make(integration.Inventory)
 	for _, repositoryProject := range googleRepositoryProjects {
-		pErr := configureProject(
+		details, pErr := configureProject(
 			ctx,
 			repositoryProject,
 			workloadIdentities[*repositoryProject.Name],
-			vaultStores[*repositoryProject.Repository],
+			sinks[*repositoryProject.Repository],
 			repositoriesConfig,
 			gcpConfig,
 			providers[*repositoryProject.Name],
@@ -63,8 +75,10 @@ func Configure(ctx *pulumi.Context,
 		if pErr != nil {
 			log.Err(pErr).
 				Msgf("[google][configure] error configuring Google Cloud resources for repository project: %s", *repositoryProject.Name)
-			return nil, pErr
+			errs = append(errs, fmt.Errorf("[google][%s] %w", *repositoryProject.Repository, pErr))
+			continue
 		}
+		inventory[*repositoryProject.Repository] = details
 
 		projectRepositoryMapping, prmOk := projects[*repositoryProject.Name]
 		if !prmOk {
@@ -81,7 +95,11 @@ func Configure(ctx *pulumi.Context,
 		}
 	}
 
-	return projects, nil
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	return projects, inventory, nil
 }
 
 // createProviders initializes GCP providers for each project specified in the configuration.
@@ -102,41 +120,39 @@ func createProviders(ctx *pulumi.Context, gcpConfig *googleConf.Config) map[stri
 
 // filterRepositoryByAllowedProjects checks if the repository's Google project
 // is included in the list of allowed projects from the configuration.
+// It returns an error describing the first unconfigured project; linked projects are checked in sorted order.
 // repoAccessPermissionsGoogle: Google access configuration for the repository.
 // gcpConfig: Google Cloud configuration details.
 func filterRepositoryByAllowedProjects(
 	repoAccessPermissionsGoogle repoConf.GoogleAccessConfig,
 	gcpConfig *googleConf.Config,
-) bool {
-	mainProject := repoAccessPermissionsGoogle.Project
-	if mainProject == nil || !slices.Contains(gcpConfig.Projects, *mainProject) {
-		log.Error().Msgf("[google][%v] the repository references an unconfigured project", *mainProject)
-		return false
+) error {
+	mainProject := defaults.GetOrDefault(repoAccessPermissionsGoogle.Project, "")
+	if !slices.Contains(gcpConfig.Projects, mainProject) {
+		return fmt.Errorf("the repository references an unconfigured project: %s", mainProject)
 	}
 
-	linkedProjects := repoAccessPermissionsGoogle.LinkedProjects
-	if linkedProjects == nil {
-		return true
-	}
-
-	for project := range linkedProjects {
+	for _, project := range slices.Sorted(maps.Keys(repoAccessPermissionsGoogle.LinkedProjects)) {
 		if !slices.Contains(gcpConfig.Projects, project) {
-			log.Error().Msgf("[google][%v] the repository references an unconfigured project", project)
-			return false
+			return fmt.Errorf("the repository references an unconfigured linked project: %s", project)
 		}
 	}
 
-	return true
+	return nil
 }
 
-// createGoogleRepositoryProjects constructs a map of Google repository projects
+// ResolveRepositoryProjects constructs a map of Google repository projects
 // based on the provided repository configurations and GCP configuration.
+// Repositories referencing unconfigured projects are skipped, or cause an error in strict mode.
 // repositories: List of repository configurations.
 // gcpConfig: Google Cloud configuration details.
-func createGoogleRepositoryProjects(
+// strict: Whether to return an error for repositories referencing unconfigured projects.
+func ResolveRepositoryProjects(
 	repositories []*repoConf.Config,
 	gcpConfig *googleConf.Config,
-) map[string]*google.RepositoryProject {
+	strict bool,
+) (map[string]*google.RepositoryProject, error) {
+	var errs []error
 	googleRepositoryProjects := make(map[string]*google.RepositoryProject)
 	for _, repository := range repositories {
 		repoAccessPermissions := defaults.GetOrDefault(
@@ -148,8 +164,16 @@ func createGoogleRepositoryProjects(
 			repoConf.GoogleAccessConfig{},
 		)
 
-		if repoAccessPermissionsGoogle.Project != nil && *repoAccessPermissionsGoogle.Project != "" &&
-			filterRepositoryByAllowedProjects(repoAccessPermissionsGoogle, gcpConfig) {
+		if repoAccessPermissionsGoogle.Project != nil && *repoAccessPermissionsGoogle.Project != "" {
+			if fErr := filterRepositoryByAllowedProjects(repoAccessPermissionsGoogle, gcpConfig); fErr != nil {
+				if strict {
+					errs = append(errs, fmt.Errorf("[google][%s] %w", repository.Name, fErr))
+				} else {
+					log.Error().Msgf("[google][%s] %s", repository.Name, fErr)
+				}
+				continue
+			}
+
 			project := defaults.GetOrDefault(repoAccessPermissionsGoogle.Project, "")
 			region := defaults.GetOrDefault(
 				repoAccessPermissionsGoogle.Region,
@@ -186,5 +210,9 @@ func createGoogleRepositoryProjects(
 		}
 	}
 
-	return googleRepositoryProjects
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return googleRepositoryProjects, nil
 }
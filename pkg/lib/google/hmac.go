@@ -1,16 +1,14 @@
 package google
 
 import (
-	"encoding/json"
 	"fmt"
 
-	"github.com/muhlba91/github-infrastructure/pkg/lib/config"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
 	"github.com/muhlba91/github-infrastructure/pkg/model/google"
-	"github.com/muhlba91/pulumi-shared-library/pkg/lib/vault/secret"
 	"github.com/pulumi/pulumi-gcp/sdk/v9/go/gcp"
 	"github.com/pulumi/pulumi-gcp/sdk/v9/go/gcp/serviceaccount"
 	"github.com/pulumi/pulumi-gcp/sdk/v9/go/gcp/storage"
-	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/rs/zerolog/log"
 )
@@ -19,12 +17,12 @@ import (
 // ctx: Pulumi context for resource management.
 // project: Google Cloud project details.
 // serviceAccount: Service account associated with the HMAC key.
-// vaultStore: Vault mount where the HMAC key will be stored.
+// sink: The credential sink of the repository.
 // provider: GCP provider for resource creation.
 func createHMACKey(ctx *pulumi.Context,
 	project *google.RepositoryProject,
 	serviceAccount *serviceaccount.Account,
-	vaultStore *vault.Mount,
+	sink integration.Sink,
 	provider *gcp.Provider,
 ) error {
 	key, err := storage.NewHmacKey(
@@ -34,34 +32,31 @@ func createHMACKey(ctx *pulumi.Context,
 			ServiceAccountEmail: serviceAccount.Email,
 			Project:             pulumi.String(*project.Name),
 		},
-		pulumi.Provider(provider),
-		pulumi.DependsOn([]pulumi.Resource{serviceAccount}),
+		component.WithRepository(
+			ctx,
+			*project.Repository,
+			pulumi.Provider(provider),
+			pulumi.DependsOn([]pulumi.Resource{serviceAccount}),
+		)...,
 	)
 	if err != nil {
 		log.Err(err).Msgf("[google][hmac] error creating HMAC key for Google Cloud project: %s", *project.Name)
 		return err
 	}
 
-	pulumi.All(vaultStore.Path, key.AccessId, key.Secret).ApplyT(func(all []any) error {
-		path, _ := all[0].(string)
-		accessID, _ := all[1].(string)
-		secretKey, _ := all[2].(string)
-
-		value, _ := json.Marshal(map[string]string{
-			"access_key_id":     accessID,
-			"secret_access_key": secretKey,
-		})
-
-		_, vErr := secret.Create(ctx, &secret.CreateOptions{
-			Path:  path,
-			Key:   "google-cloud-storage",
-			Value: pulumi.String(value),
-			PulumiOptions: []pulumi.ResourceOption{
-				pulumi.Provider(config.VaultProvider),
-			},
-		})
-		return vErr
+	vErr := sink.Store(ctx, integration.Credential{
+		Key: "google-cloud-storage",
+		Values: pulumi.StringMap{
+			"access_key_id": key.AccessId,
+		},
+		Secrets: pulumi.StringMap{
+			"secret_access_key": key.Secret,
+		},
 	})
+	if vErr != nil {
+		log.Err(vErr).Msgf("[google][hmac] error storing HMAC key for Google Cloud project: %s", *project.Name)
+		return vErr
+	}
 
 	return nil
 }
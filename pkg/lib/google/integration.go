@@ -0,0 +1,86 @@
+package google
+
+import (
+	"slices"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
+	googleConf "github.com/muhlba91/github-infrastructure/pkg/model/config/google"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// integrationName is the name of the Google Cloud integration.
+const integrationName = "google"
+
+// Integration configures Google Cloud workload identities and service accounts for repositories.
+type Integration struct {
+	// gcpConfig contains the Google Cloud configuration.
+	gcpConfig *googleConf.Config
+	// repositoriesConfig contains the repositories configuration.
+	repositoriesConfig *repositories.Config
+}
+
+// NewIntegration creates the Google Cloud integration.
+// gcpConfig: Google Cloud configuration details.
+// repositoriesConfig: Repository configuration details.
+func NewIntegration(gcpConfig *googleConf.Config, repositoriesConfig *repositories.Config) integration.Integration {
+	return &Integration{
+		gcpConfig:          gcpConfig,
+		repositoriesConfig: repositoriesConfig,
+	}
+}
+
+// Name returns the name of the integration.
+func (*Integration) Name() string {
+	return integrationName
+}
+
+// Enabled returns whether the given repository references a Google Cloud project.
+// repository: The repository configuration.
+func (*Integration) Enabled(repository *repoConf.Config) bool {
+	google := accessConfig(repository)
+	return defaults.GetOrDefault(google.Project, "") != ""
+}
+
+// Configure sets up Google Cloud resources for the given repositories.
+// ctx: The Pulumi context for resource management.
+// repositories: The repositories the integration is enabled for.
+// sinks: The credential sinks keyed by repository name.
+func (i *Integration) Configure(
+	ctx *pulumi.Context,
+	repositories []*repoConf.Config,
+	sinks map[string]integration.Sink,
+) (any, integration.Inventory, error) {
+	return Configure(ctx, repositories, sinks, i.gcpConfig, i.repositoriesConfig)
+}
+
+// Outputs returns the allowed Google Cloud projects and the repositories configured for each project.
+// configured: The repositories keyed by project returned by Configure.
+func (i *Integration) Outputs(configured any) map[string]any {
+	return map[string]any{
+		"allowed":    slices.Sorted(slices.Values(i.gcpConfig.Projects)),
+		"configured": configured,
+	}
+}
+
+// Flags returns whether Google Cloud and Google Cloud Storage access is configured for the given repository.
+// repository: The repository configuration.
+func (*Integration) Flags(repository *repoConf.Config) map[string]bool {
+	google := accessConfig(repository)
+	return map[string]bool{
+		integrationName: google.Project != nil,
+		"gcs":           defaults.GetOrDefault(google.HMACKey, false),
+	}
+}
+
+// accessConfig returns the Google Cloud access configuration of the given repository.
+// repository: The repository configuration.
+func accessConfig(repository *repoConf.Config) repoConf.GoogleAccessConfig {
+	repoAccessPermissions := defaults.GetOrDefault(
+		repository.AccessPermissions,
+		repoConf.AccessPermissionsConfig{},
+	)
+	return defaults.GetOrDefault(repoAccessPermissions.Google, repoConf.GoogleAccessConfig{})
+}
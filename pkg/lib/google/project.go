@@ -1,12 +1,12 @@
 package google
 
 import (
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
 	gcpConf "github.com/muhlba91/github-infrastructure/pkg/model/config/google"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
 	"github.com/muhlba91/github-infrastructure/pkg/model/google"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
 	"github.com/pulumi/pulumi-gcp/sdk/v9/go/gcp"
-	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/rs/zerolog/log"
 )
@@ -15,39 +15,43 @@ import (
 // ctx: Pulumi context for resource management.
 // project: Google Cloud project details.
 // workloadIdentityPool: Workload identity pool for the project.
-// vaultStore: Vault mount where secrets will be stored.
+// sink: The credential sink of the repository.
 // repositoriesConfig: Repository configuration details.
 // gcpConfig: Google Cloud configuration details.
 // provider: GCP provider for resource creation.
 func configureProject(ctx *pulumi.Context,
 	project *google.RepositoryProject,
 	workloadIdentityPool *google.WorkloadIdentityPool,
-	vaultStore *vault.Mount,
+	sink integration.Sink,
 	repositoriesConfig *repositories.Config,
 	gcpConfig *gcpConf.Config,
 	provider *gcp.Provider,
-) error {
+) (pulumi.Map, error) {
 	serviceAccount, saErr := createProjectIAM(
 		ctx,
 		project,
 		workloadIdentityPool,
-		vaultStore,
+		sink,
 		repositoriesConfig,
 		provider,
 	)
 	if saErr != nil {
 		log.Err(saErr).Msgf("[google][project] error configuring IAM for Google Cloud project: %s", *project.Name)
-		return saErr
+		return nil, saErr
 	}
 
 	if defaults.GetOrDefault(gcpConfig.AllowHMACKeys, false) && defaults.GetOrDefault(project.HMACKey, false) {
-		hmacErr := createHMACKey(ctx, project, serviceAccount, vaultStore, provider)
+		hmacErr := createHMACKey(ctx, project, serviceAccount, sink, provider)
 		if hmacErr != nil {
 			log.Err(hmacErr).
 				Msgf("[google][project] error creating HMAC key for service account in project: %s", *project.Name)
-			return hmacErr
+			return nil, hmacErr
 		}
 	}
 
-	return nil
+	return pulumi.Map{
+		"project":                  pulumi.String(*project.Name),
+		"serviceAccount":           serviceAccount.Email,
+		"workloadIdentityProvider": workloadIdentityPool.WorkloadIdentityProvider.Name,
+	}, nil
 }
@@ -1,19 +1,17 @@
 package google
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 
-	"github.com/muhlba91/github-infrastructure/pkg/lib/config"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
 	"github.com/muhlba91/github-infrastructure/pkg/model/google"
 	"github.com/muhlba91/pulumi-shared-library/pkg/lib/random"
-	"github.com/muhlba91/pulumi-shared-library/pkg/lib/vault/secret"
 	"github.com/pulumi/pulumi-gcp/sdk/v9/go/gcp"
 	"github.com/pulumi/pulumi-gcp/sdk/v9/go/gcp/projects"
 	"github.com/pulumi/pulumi-gcp/sdk/v9/go/gcp/serviceaccount"
-	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/rs/zerolog/log"
 )
@@ -22,13 +20,13 @@ import (
 // ctx: Pulumi context for resource management.
 // project: The repository project configuration.
 // workloadIdentityPool: Workload Identity Pool for the project.
-// vaultStore: Vault mount configuration.
+// sink: The credential sink of the repository.
 // repositoriesConfig: Configuration for the GitHub repositories.
 // provider: GCP provider configured for the specific project.
 func createProjectIAM(ctx *pulumi.Context,
 	project *google.RepositoryProject,
 	workloadIdentityPool *google.WorkloadIdentityPool,
-	vaultStore *vault.Mount,
+	sink integration.Sink,
 	repositoriesConfig *repositories.Config,
 	provider *gcp.Provider,
 ) (*serviceaccount.Account, error) {
@@ -38,8 +36,9 @@ func createProjectIAM(ctx *pulumi.Context,
 		ctx,
 		fmt.Sprintf("random-string-gcp-iam-role-ci-%s-%s", *project.Repository, *project.Name),
 		&random.StringOptions{
-			Length:  postfixLength,
-			Special: false,
+			Length:        postfixLength,
+			Special:       false,
+			PulumiOptions: component.WithRepository(ctx, *project.Repository),
 		},
 	)
 	if ciPfErr != nil {
@@ -77,28 +76,19 @@ func createProjectIAM(ctx *pulumi.Context,
 		return nil, saErr
 	}
 
-	pulumi.All(vaultStore.Path, workloadIdentityPool.WorkloadIdentityProvider.Name,
-		serviceAccount.Email).ApplyT(func(all []any) error {
-		path, _ := all[0].(string)
-		providerName, _ := all[1].(string)
-		email, _ := all[2].(string)
-
-		value, _ := json.Marshal(map[string]string{
-			"workload_identity_provider": providerName,
-			"ci_service_account":         email,
-			"region":                     *project.Region,
-		})
-
-		_, err := secret.Create(ctx, &secret.CreateOptions{
-			Path:  path,
-			Key:   "google-cloud",
-			Value: pulumi.String(value),
-			PulumiOptions: []pulumi.ResourceOption{
-				pulumi.Provider(config.VaultProvider),
-			},
-		})
-		return err
+	sErr := sink.Store(ctx, integration.Credential{
+		Key: "google-cloud",
+		Values: pulumi.StringMap{
+			"workload_identity_provider": workloadIdentityPool.WorkloadIdentityProvider.Name,
+			"ci_service_account":         serviceAccount.Email,
+			"region":                     pulumi.String(*project.Region),
+		},
 	})
+	if sErr != nil {
+		log.Err(sErr).
+			Msgf("[google][iam] error storing Google Cloud service account for project: %s", *project.Name)
+		return nil, sErr
+	}
 
 	return serviceAccount, nil
 }
@@ -121,14 +111,7 @@ func createCIRoles(
 	ciRoles := make(map[string]*projects.IAMCustomRole)
 
 	for _, projName := range *gcpProjects {
-		var permissions []string
-		linkedProj, ok := project.LinkedProjects[projName]
-		if projName == *project.Name || (ok && linkedProj.AccessLevel == "full") {
-			permissions = project.IAMPermissions
-		} else {
-			permissions = append([]string{}, linkedProj.IAMPermissions...)
-			permissions = append(permissions, defaultPermissions...)
-		}
+		permissions := ProjectPermissions(project, projName)
 
 		role, roleErr := projects.NewIAMCustomRole(
 			ctx,
@@ -143,7 +126,7 @@ func createCIRoles(
 				Permissions: pulumi.ToStringArray(permissions),
 				Project:     pulumi.String(projName),
 			},
-			pulumi.Provider(provider),
+			component.WithRepository(ctx, *project.Repository, pulumi.Provider(provider))...,
 		)
 		if roleErr != nil {
 			log.Err(roleErr).Msgf("[google][iam] error creating Google Cloud IAM role for project: %s", projName)
@@ -191,7 +174,7 @@ func createServiceAccount(
 			),
 			Project: pulumi.String(*project.Name),
 		},
-		pulumi.Provider(provider),
+		component.WithRepository(ctx, *project.Repository, pulumi.Provider(provider))...,
 	)
 	if saErr != nil {
 		log.Err(saErr).
@@ -208,11 +191,15 @@ func createServiceAccount(
 				Role:    ciRoles[projName].ID(),
 				Member:  pulumi.Sprintf("serviceAccount:%s", serviceAccount.Email),
 			},
-			pulumi.Provider(provider),
-			pulumi.DependsOn([]pulumi.Resource{
-				serviceAccount,
-				ciRoles[projName],
-			}),
+			component.WithRepository(
+				ctx,
+				*project.Repository,
+				pulumi.Provider(provider),
+				pulumi.DependsOn([]pulumi.Resource{
+					serviceAccount,
+					ciRoles[projName],
+				}),
+			)...,
 		)
 		if mbrErr != nil {
 			log.Err(mbrErr).Msgf("[google][iam] error assigning IAM role to service account for project: %s", projName)
@@ -235,11 +222,15 @@ func createServiceAccount(
 				),
 			},
 		},
-		pulumi.Provider(provider),
-		pulumi.DependsOn([]pulumi.Resource{
-			serviceAccount,
-			workloadIdentityPool.WorkloadIdentityProvider,
-		}),
+		component.WithRepository(
+			ctx,
+			*project.Repository,
+			pulumi.Provider(provider),
+			pulumi.DependsOn([]pulumi.Resource{
+				serviceAccount,
+				workloadIdentityPool.WorkloadIdentityProvider,
+			}),
+		)...,
 	)
 	if bindErr != nil {
 		log.Err(bindErr).
@@ -249,3 +240,21 @@ func createServiceAccount(
 
 	return serviceAccount, nil
 }
+
+// ProjectPermissions returns the effective IAM permissions of a repository in the given Google Cloud project.
+// The repository's project and linked projects with full access are granted the repository's permissions,
+// other linked projects their own permissions and the default permissions.
+// project: The repository project configuration.
+// gcpProject: The Google Cloud project ID.
+func ProjectPermissions(project *google.RepositoryProject, gcpProject string) []string {
+	linkedProj, ok := project.LinkedProjects[gcpProject]
+	if gcpProject == *project.Name || (ok && linkedProj.AccessLevel == "full") {
+		return project.IAMPermissions
+	}
+
+	var permissions []string
+	if ok {
+		permissions = append(permissions, linkedProj.IAMPermissions...)
+	}
+	return append(permissions, defaultPermissions...)
+}
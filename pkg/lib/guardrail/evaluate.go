@@ -0,0 +1,233 @@
+package guardrail
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	guardrailConf "github.com/muhlba91/github-infrastructure/pkg/model/config/guardrail"
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/stack"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// wildcard matches any key of a mapping or any element of a list in a condition's path.
+const wildcard = "*"
+
+// Violation is a violation of a guardrail rule by a repository.
+type Violation struct {
+	// Repository is the name of the repository.
+	Repository string
+	// Rule is the name of the violated rule.
+	Rule string
+	// Level is the level of the violated rule.
+	Level string
+	// Description is the description of the violated rule.
+	Description string
+}
+
+// Error returns the violation as an error message.
+func (v Violation) Error() string {
+	return fmt.Sprintf("[%s] guardrail '%s' violated: %s", v.Repository, v.Rule, v.Description)
+}
+
+// Check evaluates the guardrail rules against the repositories.
+// Violations of 'warn' rules are logged, while violations of 'deny' rules are returned as an error.
+// config: The guardrail rules.
+// repositories: The repository configurations.
+// stackConfig: The project configuration of the stack.
+func Check(config *guardrailConf.Config, repositories []*repoConf.Config, stackConfig *stack.Config) error {
+	violations, err := Evaluate(config, repositories, stackConfig)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, violation := range violations {
+		if violation.Level == LevelDeny {
+			errs = append(errs, violation)
+			continue
+		}
+		log.Warn().Msgf("[guardrail] %s", violation)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Evaluate evaluates the guardrail rules against the repositories, and returns all violations.
+// Each rule is evaluated against a document with the repository's configuration at 'repository',
+// and the stack's configuration at 'stack'.
+// config: The guardrail rules.
+// repositories: The repository configurations.
+// stackConfig: The project configuration of the stack.
+func Evaluate(
+	config *guardrailConf.Config,
+	repositories []*repoConf.Config,
+	stackConfig *stack.Config,
+) ([]Violation, error) {
+	if len(config.Rules) == 0 {
+		return nil, nil
+	}
+
+	stackDocument, sErr := toDocument(stackConfig)
+	if sErr != nil {
+		log.Err(sErr).Msg("[guardrail] error converting stack configuration")
+		return nil, sErr
+	}
+
+	var violations []Violation
+	for _, repository := range repositories {
+		repositoryDocument, rErr := toDocument(repository)
+		if rErr != nil {
+			log.Err(rErr).Msgf("[guardrail] error converting repository configuration: %s", repository.Name)
+			return nil, rErr
+		}
+		document := map[string]any{
+			"repository": repositoryDocument,
+			"stack":      stackDocument,
+		}
+
+		for _, rule := range config.Rules {
+			if !allHold(rule.When, document) || allHold(rule.Require, document) {
+				continue
+			}
+			violations = append(violations, Violation{
+				Repository:  repository.Name,
+				Rule:        rule.Name,
+				Level:       rule.Level,
+				Description: rule.Description,
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+// toDocument converts the given configuration to plain YAML values using its YAML field names.
+// value: The configuration.
+func toDocument(value any) (any, error) {
+	b, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var document any
+	if uErr := yaml.Unmarshal(b, &document); uErr != nil {
+		return nil, uErr
+	}
+	return document, nil
+}
+
+// allHold returns whether all conditions hold for the given document.
+// conditions: The conditions.
+// document: The evaluated document.
+func allHold(conditions []guardrailConf.Condition, document any) bool {
+	for _, condition := range conditions {
+		if !holds(condition, document) {
+			return false
+		}
+	}
+	return true
+}
+
+// holds returns whether the condition holds for the given document.
+// condition: The condition.
+// document: The evaluated document.
+func holds(condition guardrailConf.Condition, document any) bool {
+	values := resolve(document, strings.Split(condition.Path, "."))
+	if len(values) == 0 && condition.Default != nil {
+		values = []any{condition.Default}
+	}
+
+	switch {
+	case condition.Exists != nil:
+		return (len(values) > 0) == *condition.Exists
+	case condition.Equals != nil:
+		return slices.ContainsFunc(values, equals(condition.Equals))
+	case condition.NotEquals != nil:
+		return !slices.ContainsFunc(values, equals(condition.NotEquals))
+	case condition.Contains != nil:
+		return slices.ContainsFunc(values, contains(condition.Contains))
+	case condition.NotContains != nil:
+		return !slices.ContainsFunc(values, contains(condition.NotContains))
+	case condition.Matches != "":
+		return slices.ContainsFunc(values, matches(condition.Matches))
+	case condition.NotMatches != "":
+		return !slices.ContainsFunc(values, matches(condition.NotMatches))
+	default:
+		return true
+	}
+}
+
+// resolve returns the non-null values at the given path below the given value.
+// value: The value to start from.
+// path: The path segments, which may contain wildcards.
+func resolve(value any, path []string) []any {
+	if value == nil {
+		return nil
+	}
+	if len(path) == 0 {
+		return []any{value}
+	}
+
+	var children []any
+	switch v := value.(type) {
+	case map[string]any:
+		if path[0] != wildcard {
+			return resolve(v[path[0]], path[1:])
+		}
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			children = append(children, v[key])
+		}
+	case []any:
+		if path[0] != wildcard {
+			return nil
+		}
+		children = v
+	default:
+		return nil
+	}
+
+	var values []any
+	for _, child := range children {
+		values = append(values, resolve(child, path[1:])...)
+	}
+	return values
+}
+
+// equals returns a predicate matching values equal to the expected value.
+// Values are compared by their string representation, hence 'true' matches true.
+// expected: The expected value.
+func equals(expected any) func(any) bool {
+	return func(value any) bool {
+		return fmt.Sprint(value) == fmt.Sprint(expected)
+	}
+}
+
+// contains returns a predicate matching lists containing the expected value.
+// expected: The expected value.
+func contains(expected any) func(any) bool {
+	return func(value any) bool {
+		list, ok := value.([]any)
+		return ok && slices.ContainsFunc(list, equals(expected))
+	}
+}
+
+// matches returns a predicate matching scalar values whose string representation matches the regular expression.
+// An invalid regular expression matches no value; expressions are validated when loading the rules.
+// expression: The regular expression.
+func matches(expression string) func(any) bool {
+	re, err := regexp.Compile(expression)
+	return func(value any) bool {
+		switch value.(type) {
+		case map[string]any, []any:
+			return false
+		default:
+			return err == nil && re.MatchString(fmt.Sprint(value))
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package guardrail_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/guardrail"
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/stack"
+)
+
+const (
+	// rulesFile is the file containing the repository's guardrail rules.
+	rulesFile = "../../../assets/guardrails.yml"
+	// adminPermissionsRule is the rule denying wildcard and owner-level permissions to public repositories.
+	adminPermissionsRule = "public-repositories-no-admin-permissions"
+	// hmacKeysRule is the rule denying HMAC keys to public repositories.
+	hmacKeysRule = "hmac-keys-require-private-repositories"
+)
+
+// TestAdminPermissions evaluates the repository's guardrail rules against repositories requesting
+// wildcard or owner-level permissions of every cloud.
+func TestAdminPermissions(t *testing.T) {
+	rules, err := guardrail.Load(rulesFile)
+	if err != nil {
+		t.Fatalf("error loading guardrail rules: %v", err)
+	}
+
+	public, private := "public", "private"
+	tests := []struct {
+		name              string
+		visibility        *string
+		accessPermissions repoConf.AccessPermissionsConfig
+		denied            bool
+	}{
+		{
+			name:       "aws scoped permissions",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Aws: &repoConf.AwsAccessConfig{IAMPermissions: []string{"s3:ListBucket", "iam:GetRole"}},
+			},
+		},
+		{
+			name:       "aws iam wildcard",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Aws: &repoConf.AwsAccessConfig{IAMPermissions: []string{"iam:*"}},
+			},
+			denied: true,
+		},
+		{
+			name:       "aws iam wildcard in a wider list",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Aws: &repoConf.AwsAccessConfig{IAMPermissions: []string{"s3:ListBucket", "iam:*", "s3:GetObject"}},
+			},
+			denied: true,
+		},
+		{
+			name:       "aws all actions",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Aws: &repoConf.AwsAccessConfig{IAMPermissions: []string{"*"}},
+			},
+			denied: true,
+		},
+		{
+			name:       "aws all services and actions",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Aws: &repoConf.AwsAccessConfig{IAMPermissions: []string{"*:*"}},
+			},
+			denied: true,
+		},
+		{
+			name:       "aws iam wildcard in different case",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Aws: &repoConf.AwsAccessConfig{IAMPermissions: []string{"IAM:*"}},
+			},
+			denied: true,
+		},
+		{
+			name:       "aws partial iam wildcard",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Aws: &repoConf.AwsAccessConfig{IAMPermissions: []string{"iam:Put*"}},
+			},
+			denied: true,
+		},
+		{
+			name: "aws iam wildcard with default visibility",
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Aws: &repoConf.AwsAccessConfig{IAMPermissions: []string{"iam:*"}},
+			},
+			denied: true,
+		},
+		{
+			name:       "aws iam wildcard of private repository",
+			visibility: &private,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Aws: &repoConf.AwsAccessConfig{IAMPermissions: []string{"*", "iam:*"}},
+			},
+		},
+		{
+			name:       "google scoped permissions",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Google: &repoConf.GoogleAccessConfig{IAMPermissions: []string{"dns.managedZones.get", "iam.roles.get"}},
+			},
+		},
+		{
+			name:       "google owner role",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Google: &repoConf.GoogleAccessConfig{IAMPermissions: []string{"dns.managedZones.get", "roles/owner"}},
+			},
+			denied: true,
+		},
+		{
+			name:       "google iam role",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Google: &repoConf.GoogleAccessConfig{IAMPermissions: []string{"roles/iam.securityAdmin"}},
+			},
+			denied: true,
+		},
+		{
+			name:       "google iam wildcard",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Google: &repoConf.GoogleAccessConfig{IAMPermissions: []string{"iam.*"}},
+			},
+			denied: true,
+		},
+		{
+			name:       "google owner role of linked project",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Google: &repoConf.GoogleAccessConfig{
+					LinkedProjects: map[string]repoConf.GoogleLinkedAccessConfig{
+						"linked": {AccessLevel: "default", IAMPermissions: []string{"roles/owner"}},
+					},
+				},
+			},
+			denied: true,
+		},
+		{
+			name:       "scaleway scoped permission sets",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Scaleway: &repoConf.ScalewayAccessConfig{IAMPermissions: []string{"DomainsDNSFullAccess"}},
+			},
+		},
+		{
+			name:       "scaleway iam manager",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Scaleway: &repoConf.ScalewayAccessConfig{IAMPermissions: []string{"DomainsDNSFullAccess", "IAMManager"}},
+			},
+			denied: true,
+		},
+		{
+			name:       "scaleway full access to all products",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Scaleway: &repoConf.ScalewayAccessConfig{IAMPermissions: []string{"AllProductsFullAccess"}},
+			},
+			denied: true,
+		},
+		{
+			name:       "scaleway iam manager of linked project",
+			visibility: &public,
+			accessPermissions: repoConf.AccessPermissionsConfig{
+				Scaleway: &repoConf.ScalewayAccessConfig{
+					LinkedProjects: map[string]repoConf.ScalewayLinkedAccessConfig{
+						"linked": {AccessLevel: "default", IAMPermissions: []string{"IAMManager"}},
+					},
+				},
+			},
+			denied: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repository := &repoConf.Config{
+				Name:              "repository",
+				Visibility:        test.visibility,
+				AccessPermissions: &test.accessPermissions,
+			}
+
+			violations, eErr := guardrail.Evaluate(rules, []*repoConf.Config{repository}, &stack.Config{})
+			if eErr != nil {
+				t.Fatalf("error evaluating guardrail rules: %v", eErr)
+			}
+
+			denied := slices.ContainsFunc(violations, func(violation guardrail.Violation) bool {
+				return violation.Rule == adminPermissionsRule && violation.Level == guardrail.LevelDeny
+			})
+			if denied != test.denied {
+				t.Errorf("expected denied to be %t, got violations: %v", test.denied, violations)
+			}
+		})
+	}
+}
+
+// TestHMACKeys evaluates the repository's guardrail rules against repositories with HMAC keys,
+// including the explicitly exempted repository.
+func TestHMACKeys(t *testing.T) {
+	rules, err := guardrail.Load(rulesFile)
+	if err != nil {
+		t.Fatalf("error loading guardrail rules: %v", err)
+	}
+
+	public, private, enabled := "public", "private", true
+	tests := []struct {
+		name       string
+		repository string
+		visibility *string
+		denied     bool
+	}{
+		{name: "public repository", repository: "repository", visibility: &public, denied: true},
+		{name: "default visibility", repository: "repository", denied: true},
+		{name: "private repository", repository: "repository", visibility: &private},
+		{name: "exempted repository", repository: "homelab-esphome-firmware", visibility: &public},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repository := &repoConf.Config{
+				Name:       test.repository,
+				Visibility: test.visibility,
+				AccessPermissions: &repoConf.AccessPermissionsConfig{
+					Google: &repoConf.GoogleAccessConfig{HMACKey: &enabled},
+				},
+			}
+
+			violations, eErr := guardrail.Evaluate(rules, []*repoConf.Config{repository}, &stack.Config{})
+			if eErr != nil {
+				t.Fatalf("error evaluating guardrail rules: %v", eErr)
+			}
+
+			denied := slices.ContainsFunc(violations, func(violation guardrail.Violation) bool {
+				return violation.Rule == hmacKeysRule && violation.Level == guardrail.LevelDeny
+			})
+			if denied != test.denied {
+				t.Errorf("expected denied to be %t, got violations: %v", test.denied, violations)
+			}
+		})
+	}
+}
+
+// TestLoadInvalidExpression checks that rules with invalid regular expressions are rejected.
+func TestLoadInvalidExpression(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "guardrails.yml")
+	rules := []byte(`rules:
+  - name: invalid
+    level: deny
+    require:
+      - path: repository.name
+        matches: "("
+`)
+	if err := os.WriteFile(file, rules, 0o600); err != nil {
+		t.Fatalf("error writing guardrail rules: %v", err)
+	}
+
+	if _, err := guardrail.Load(file); err == nil {
+		t.Error("expected an error for an invalid regular expression")
+	}
+}
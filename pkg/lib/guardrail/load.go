@@ -0,0 +1,129 @@
+package guardrail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	guardrailConf "github.com/muhlba91/github-infrastructure/pkg/model/config/guardrail"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// The levels of a rule.
+const (
+	// LevelWarn reports violations of a rule.
+	LevelWarn = "warn"
+	// LevelDeny reports violations of a rule and fails the run.
+	LevelDeny = "deny"
+)
+
+// roots are the allowed first segments of a condition's path.
+//
+//nolint:gochecknoglobals // constant lookup table
+var roots = []string{"repository", "stack"}
+
+// Load reads the guardrail rules from the given file.
+// A missing file results in no rules; unknown fields and invalid rules are reported as errors.
+// file: The path of the rules file.
+func Load(file string) (*guardrailConf.Config, error) {
+	b, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &guardrailConf.Config{}, nil
+	}
+	if err != nil {
+		log.Err(err).Msgf("[guardrail] error reading guardrail rules: %s", file)
+		return nil, err
+	}
+
+	var config guardrailConf.Config
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if dErr := decoder.Decode(&config); dErr != nil && !errors.Is(dErr, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", file, dErr)
+	}
+
+	if vErr := validateRules(config.Rules); vErr != nil {
+		return nil, fmt.Errorf("%s: %w", file, vErr)
+	}
+
+	return &config, nil
+}
+
+// validateRules checks that the rules are named uniquely, have a valid level,
+// require at least one condition, and that every condition has a valid path and exactly one operator.
+// rules: The guardrail rules.
+func validateRules(rules []guardrailConf.Rule) error {
+	var errs []error
+
+	var names []string
+	for i, rule := range rules {
+		if rule.Name == "" {
+			errs = append(errs, fmt.Errorf("rule %d: missing required field 'name'", i))
+		} else if slices.Contains(names, rule.Name) {
+			errs = append(errs, fmt.Errorf("rule '%s': duplicate rule name", rule.Name))
+		}
+		names = append(names, rule.Name)
+
+		if rule.Level != LevelWarn && rule.Level != LevelDeny {
+			errs = append(errs, fmt.Errorf("rule '%s': invalid level '%s'; allowed values: %s, %s",
+				rule.Name, rule.Level, LevelWarn, LevelDeny))
+		}
+		if len(rule.Require) == 0 {
+			errs = append(errs, fmt.Errorf("rule '%s': missing required field 'require'", rule.Name))
+		}
+
+		for _, condition := range slices.Concat(rule.When, rule.Require) {
+			if cErr := validateCondition(condition); cErr != nil {
+				errs = append(errs, fmt.Errorf("rule '%s': %w", rule.Name, cErr))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateCondition checks that the condition's path is rooted at a known document, that it has exactly one operator,
+// and that its regular expression, if any, is valid.
+// condition: The condition.
+func validateCondition(condition guardrailConf.Condition) error {
+	root, _, _ := strings.Cut(condition.Path, ".")
+	if !slices.Contains(roots, root) {
+		return fmt.Errorf("invalid path '%s'; paths must start with: %s", condition.Path, strings.Join(roots, ", "))
+	}
+
+	operators := 0
+	for _, set := range []bool{
+		condition.Equals != nil,
+		condition.NotEquals != nil,
+		condition.Contains != nil,
+		condition.NotContains != nil,
+		condition.Exists != nil,
+		condition.Matches != "",
+		condition.NotMatches != "",
+	} {
+		if set {
+			operators++
+		}
+	}
+	if operators != 1 {
+		return fmt.Errorf(
+			"condition on '%s' must have exactly one of: equals, notEquals, contains, notContains, matches, notMatches, exists",
+			condition.Path,
+		)
+	}
+
+	if expression := condition.Matches + condition.NotMatches; expression != "" {
+		if _, rErr := regexp.Compile(expression); rErr != nil {
+			return fmt.Errorf("condition on '%s': invalid regular expression: %w", condition.Path, rErr)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package guide
+
+import (
+	"fmt"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/sink"
+	vaultLib "github.com/muhlba91/github-infrastructure/pkg/lib/vault"
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/stack"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
+	"github.com/rs/zerolog/log"
+)
+
+// templatePath is the path of the usage guide template.
+const templatePath = "assets/templates/guide.md.tpl"
+
+// Render renders the usage guide of the given repository.
+// The guide lists the secrets written to the repository's credential sink,
+// and contains a GitHub Actions job authenticating to Vault, if used, and every configured cloud.
+// repository: The repository configuration.
+// stackConfig: The project configuration of the stack.
+func Render(repository *repoConf.Config, stackConfig *stack.Config) (string, error) {
+	kind := Sink(repository)
+	secrets := Secrets(repository, stackConfig)
+	has := make(map[string]bool)
+	// the expressions referencing the fields in GitHub Actions keyed by their environment variable
+	refs := make(map[string]string)
+	for _, secret := range secrets {
+		has[secret.Key] = true
+		for _, field := range secret.Fields {
+			refs[field.Env] = reference(kind, secret.Key, field)
+		}
+	}
+
+	// the GitHub Actions secrets containing the scoped Vault roles
+	var roles []string
+	if kind == sink.Vault && len(secrets) > 0 && repository.AccessPermissions != nil &&
+		repository.AccessPermissions.Vault != nil {
+		for _, role := range repository.AccessPermissions.Vault.Roles {
+			roles = append(roles, vaultLib.RoleSecretName(role.Name))
+		}
+	}
+
+	guide, err := template.Render(templatePath, map[string]any{
+		"repository": repository.Name,
+		"mount":      vaultLib.StorePath(repository.Name),
+		"sink":       kind,
+		"secrets":    secrets,
+		"has":        has,
+		"refs":       refs,
+		"roles":      roles,
+	})
+	if err != nil {
+		log.Err(err).Msgf("[guide] error rendering usage guide for repository: %s", repository.Name)
+		return "", err
+	}
+
+	return guide, nil
+}
+
+// reference returns the expression referencing a field of a secret in a GitHub Actions workflow.
+// Fields stored in Vault are referenced by the outputs of the Vault step, all others by their secret or variable.
+// kind: The kind of credential sink of the repository.
+// key: The key of the secret.
+// field: The field of the secret.
+func reference(kind string, key string, field Field) string {
+	switch {
+	case kind == sink.Vault:
+		return fmt.Sprintf("steps.vault.outputs.%s", field.Env)
+	case kind == sink.Variables && !field.Sensitive:
+		return fmt.Sprintf("vars.%s", sink.Name(key, field.Name))
+	default:
+		return fmt.Sprintf("secrets.%s", sink.Name(key, field.Name))
+	}
+}
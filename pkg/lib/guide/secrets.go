@@ -0,0 +1,208 @@
+package guide
+
+import (
+	"slices"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/sink"
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/stack"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+)
+
+// Field is a field of a secret stored in a repository's credential sink.
+type Field struct {
+	// Name is the name of the field.
+	Name string
+	// Env is the environment variable the field is exported as in GitHub Actions.
+	Env string
+	// Sensitive indicates whether the field is stored as a secret if non-sensitive fields are stored as variables.
+	Sensitive bool
+}
+
+// Secret is a secret stored in a repository's credential sink.
+type Secret struct {
+	// Key is the key of the secret.
+	Key string
+	// Description describes the purpose of the secret.
+	Description string
+	// Fields are the fields of the secret.
+	Fields []Field
+}
+
+// The secrets written by the Vault and integration packages; the keys, fields, and their sensitivity must be kept in sync with them,
+// which is checked by the program's tests.
+//
+//nolint:gochecknoglobals // lookup table of the written secrets
+var (
+	vaultSecret = Secret{
+		Key:         "vault",
+		Description: "Vault JWT authentication of the repository",
+		Fields: []Field{
+			{Name: "address", Env: "VAULT_ADDR"},
+			{Name: "role", Env: "VAULT_ROLE"},
+			{Name: "path", Env: "VAULT_PATH"},
+		},
+	}
+	awsSecret = Secret{
+		Key:         "aws",
+		Description: "AWS IAM role assumed via GitHub OIDC",
+		Fields: []Field{
+			{Name: "identity_role_arn", Env: "AWS_ROLE_ARN"},
+			{Name: "region", Env: "AWS_REGION"},
+		},
+	}
+	googleSecret = Secret{
+		Key:         "google-cloud",
+		Description: "Google Cloud service account impersonated via Workload Identity Federation",
+		Fields: []Field{
+			{Name: "workload_identity_provider", Env: "GOOGLE_WORKLOAD_IDENTITY_PROVIDER"},
+			{Name: "ci_service_account", Env: "GOOGLE_SERVICE_ACCOUNT"},
+			{Name: "region", Env: "CLOUDSDK_COMPUTE_REGION"},
+		},
+	}
+	googleStorageSecret = Secret{
+		Key:         "google-cloud-storage",
+		Description: "Google Cloud Storage HMAC key of the service account",
+		Fields: []Field{
+			{Name: "access_key_id", Env: "GCS_ACCESS_KEY_ID"},
+			{Name: "secret_access_key", Env: "GCS_SECRET_ACCESS_KEY", Sensitive: true},
+		},
+	}
+	scalewaySecret = Secret{
+		Key:         "scaleway",
+		Description: "Scaleway API key of the IAM application",
+		Fields: []Field{
+			{Name: "access_key", Env: "SCW_ACCESS_KEY"},
+			{Name: "secret_key", Env: "SCW_SECRET_KEY", Sensitive: true},
+			{Name: "region", Env: "SCW_DEFAULT_REGION"},
+			{Name: "zone", Env: "SCW_DEFAULT_ZONE"},
+			{Name: "organization_id", Env: "SCW_DEFAULT_ORGANIZATION_ID"},
+			{Name: "project_id", Env: "SCW_DEFAULT_PROJECT_ID"},
+		},
+	}
+	gitlabSecret = Secret{
+		Key:         "gitlab",
+		Description: "GitLab group access token",
+		Fields: []Field{
+			{Name: "token", Env: "GITLAB_TOKEN", Sensitive: true},
+		},
+	}
+	tailscaleSecret = Secret{
+		Key:         "tailscale",
+		Description: "Tailscale OAuth client",
+		Fields: []Field{
+			{Name: "oauth_client_id", Env: "TS_OAUTH_CLIENT_ID"},
+			{Name: "oauth_secret", Env: "TS_OAUTH_SECRET", Sensitive: true},
+		},
+	}
+)
+
+// Sink returns the kind of credential sink of the given repository.
+// repository: The repository configuration.
+func Sink(repository *repoConf.Config) string {
+	return sink.Kind(repository)
+}
+
+// Secrets returns the secrets which are written to the credential sink of the given repository.
+// Integrations referencing unconfigured Google Cloud projects, AWS accounts, or Scaleway projects are skipped,
+// as they are during a deployment; no secrets are written if the sink is Vault, but it is unavailable for the repository.
+// repository: The repository configuration.
+// stackConfig: The project configuration of the stack.
+func Secrets(repository *repoConf.Config, stackConfig *stack.Config) []Secret {
+	accessPermissions := defaults.GetOrDefault(repository.AccessPermissions, repoConf.AccessPermissionsConfig{})
+	available := vaultAvailable(repository, accessPermissions, stackConfig)
+
+	var secrets []Secret
+	if sink.Kind(repository) == sink.Vault {
+		if !available {
+			return nil
+		}
+		secrets = append(secrets, vaultSecret)
+	}
+	if google := accessPermissions.Google; google != nil && googleConfigured(google, stackConfig) {
+		secrets = append(secrets, googleSecret)
+		if defaults.GetOrDefault(stackConfig.Google.AllowHMACKeys, false) && defaults.GetOrDefault(google.HMACKey, false) {
+			secrets = append(secrets, googleStorageSecret)
+		}
+	}
+	if aws := accessPermissions.Aws; aws != nil && awsConfigured(aws, stackConfig) {
+		secrets = append(secrets, awsSecret)
+	}
+	if scaleway := accessPermissions.Scaleway; scaleway != nil && scalewayConfigured(scaleway, stackConfig) {
+		secrets = append(secrets, scalewaySecret)
+	}
+	if accessPermissions.GitLab != nil && len(accessPermissions.GitLab.Scopes) > 0 {
+		secrets = append(secrets, gitlabSecret)
+	}
+	if defaults.GetOrDefault(accessPermissions.Tailscale, false) {
+		secrets = append(secrets, tailscaleSecret)
+	}
+
+	return secrets
+}
+
+// vaultAvailable returns whether a Vault mount is created for the given repository.
+// repository: The repository configuration.
+// accessPermissions: The access permissions of the repository.
+// stackConfig: The project configuration of the stack.
+func vaultAvailable(
+	repository *repoConf.Config,
+	accessPermissions repoConf.AccessPermissionsConfig,
+	stackConfig *stack.Config,
+) bool {
+	vaultAccessPermissions := defaults.GetOrDefault(
+		accessPermissions.Vault,
+		repoConf.VaultAccessPermissionsConfig{},
+	)
+	return stackConfig.Vault != nil && defaults.GetOrDefault(stackConfig.Vault.Enabled, false) &&
+		defaults.GetOrDefault(repository.ManageLifecycle, true) &&
+		defaults.GetOrDefault(vaultAccessPermissions.Enabled, true)
+}
+
+// googleConfigured returns whether the Google Cloud projects referenced by a repository are configured.
+// google: The Google Cloud access configuration of the repository.
+// stackConfig: The project configuration of the stack.
+func googleConfigured(google *repoConf.GoogleAccessConfig, stackConfig *stack.Config) bool {
+	if defaults.GetOrDefault(google.Project, "") == "" || stackConfig.Google == nil {
+		return false
+	}
+
+	if !slices.Contains(stackConfig.Google.Projects, *google.Project) {
+		return false
+	}
+	for project := range google.LinkedProjects {
+		if !slices.Contains(stackConfig.Google.Projects, project) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// awsConfigured returns whether the AWS account referenced by a repository is configured.
+// aws: The AWS access configuration of the repository.
+// stackConfig: The project configuration of the stack.
+func awsConfigured(aws *repoConf.AwsAccessConfig, stackConfig *stack.Config) bool {
+	return defaults.GetOrDefault(aws.Account, "") != "" &&
+		stackConfig.Aws != nil && stackConfig.Aws.Account[*aws.Account] != nil
+}
+
+// scalewayConfigured returns whether the Scaleway projects referenced by a repository are configured.
+// scaleway: The Scaleway access configuration of the repository.
+// stackConfig: The project configuration of the stack.
+func scalewayConfigured(scaleway *repoConf.ScalewayAccessConfig, stackConfig *stack.Config) bool {
+	if defaults.GetOrDefault(scaleway.Project, "") == "" || stackConfig.Scaleway == nil {
+		return false
+	}
+
+	if stackConfig.Scaleway.Projects[*scaleway.Project] == nil {
+		return false
+	}
+	for project := range scaleway.LinkedProjects {
+		if stackConfig.Scaleway.Projects[project] == nil {
+			return false
+		}
+	}
+
+	return true
+}
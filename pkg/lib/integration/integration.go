@@ -0,0 +1,49 @@
+package integration
+
+import (
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Integration defines a credential target which is configured for repositories,
+// and whose credentials are stored in the repositories' sinks.
+type Integration interface {
+	// Name returns the unique name of the integration, which is used as the key of its outputs.
+	Name() string
+	// Enabled returns whether the integration is enabled for the given repository.
+	// repository: The repository configuration.
+	Enabled(repository *repository.Config) bool
+	// Configure configures the integration for the given repositories.
+	// It returns the value of the integration's 'configured' output, and the inventory of the configured credentials.
+	// ctx: The Pulumi context for resource management.
+	// repositories: The repositories the integration is enabled for.
+	// sinks: The credential sinks keyed by repository name.
+	Configure(
+		ctx *pulumi.Context,
+		repositories []*repository.Config,
+		sinks map[string]Sink,
+	) (any, Inventory, error)
+	// Outputs returns the outputs of the integration.
+	// configured: The value returned by Configure.
+	Outputs(configured any) map[string]any
+	// Flags returns the per-repository flags of the integration, keyed by flag name.
+	// repository: The repository configuration.
+	Flags(repository *repository.Config) map[string]bool
+}
+
+// Inventory contains the non-secret details of the credentials configured for repositories, keyed by repository name.
+type Inventory map[string]pulumi.Map
+
+// Filter returns the repositories the given integration is enabled for.
+// integration: The integration.
+// repositories: A slice of repository configurations.
+func Filter(integration Integration, repositories []*repository.Config) []*repository.Config {
+	var repos []*repository.Config
+	for _, repository := range repositories {
+		if integration.Enabled(repository) {
+			repos = append(repos, repository)
+		}
+	}
+
+	return repos
+}
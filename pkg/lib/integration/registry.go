@@ -0,0 +1,41 @@
+package integration
+
+import "fmt"
+
+// Registry holds the integrations configured for repositories.
+type Registry struct {
+	// integrations contains the registered integrations in registration order.
+	integrations []Integration
+}
+
+// NewRegistry creates a registry containing the given integrations.
+// integrations: The integrations to register.
+func NewRegistry(integrations ...Integration) (*Registry, error) {
+	registry := &Registry{}
+	for _, integration := range integrations {
+		if err := registry.Register(integration); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
+
+// Register adds the given integration to the registry.
+// Integration names must be unique.
+// integration: The integration to register.
+func (r *Registry) Register(integration Integration) error {
+	for _, registered := range r.integrations {
+		if registered.Name() == integration.Name() {
+			return fmt.Errorf("integration already registered: %s", integration.Name())
+		}
+	}
+
+	r.integrations = append(r.integrations, integration)
+	return nil
+}
+
+// Integrations returns the registered integrations in registration order.
+func (r *Registry) Integrations() []Integration {
+	return r.integrations
+}
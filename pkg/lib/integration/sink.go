@@ -0,0 +1,33 @@
+package integration
+
+import "github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+// Sink stores the credentials configured for a repository, e.g. in its Vault mount or as GitHub Actions secrets.
+type Sink interface {
+	// Store stores the given credential.
+	// ctx: The Pulumi context for resource management.
+	// credential: The credential to store.
+	Store(ctx *pulumi.Context, credential Credential) error
+}
+
+// Credential is a credential configured for a repository.
+type Credential struct {
+	// Key is the key of the credential, e.g. 'aws'.
+	Key string
+	// Values are the non-sensitive fields of the credential.
+	Values pulumi.StringMap
+	// Secrets are the sensitive fields of the credential.
+	Secrets pulumi.StringMap
+}
+
+// Fields returns all fields of the credential.
+func (c Credential) Fields() pulumi.StringMap {
+	fields := make(pulumi.StringMap, len(c.Values)+len(c.Secrets))
+	for name, value := range c.Values {
+		fields[name] = value
+	}
+	for name, value := range c.Secrets {
+		fields[name] = value
+	}
+	return fields
+}
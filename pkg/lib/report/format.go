@@ -0,0 +1,103 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The supported output formats.
+const (
+	// FormatJSON renders the entries as a JSON array.
+	FormatJSON = "json"
+	// FormatCSV renders the entries as CSV with a header row.
+	FormatCSV = "csv"
+	// FormatMarkdown renders the entries as a Markdown table.
+	FormatMarkdown = "markdown"
+)
+
+// csvHeader is the header row of the CSV format.
+//
+//nolint:gochecknoglobals // constant header row
+var csvHeader = []string{"repository", "provider", "target", "accessLevel", "permissions"}
+
+// Write writes the entries in the given format.
+// w: The writer to write to.
+// entries: The entries of the access matrix.
+// format: The output format: json, csv, or markdown.
+func Write(w io.Writer, entries []Entry, format string) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, entries)
+	case FormatCSV:
+		return writeCSV(w, entries)
+	case FormatMarkdown:
+		return writeMarkdown(w, entries)
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+}
+
+// writeJSON writes the entries as an indented JSON array.
+// w: The writer to write to.
+// entries: The entries of the access matrix.
+func writeJSON(w io.Writer, entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
+
+// writeCSV writes the entries as CSV, one row per repository and target, with space-separated permissions.
+// w: The writer to write to.
+// entries: The entries of the access matrix.
+func writeCSV(w io.Writer, entries []Entry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := writer.Write([]string{
+			entry.Repository,
+			entry.Provider,
+			entry.Target,
+			entry.AccessLevel,
+			strings.Join(entry.Permissions, " "),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeMarkdown writes the entries as a Markdown table, one row per repository and target.
+// w: The writer to write to.
+// entries: The entries of the access matrix.
+func writeMarkdown(w io.Writer, entries []Entry) error {
+	var sb strings.Builder
+	sb.WriteString("| Repository | Provider | Target | Access Level | Permissions |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, entry := range entries {
+		permissions := make([]string, 0, len(entry.Permissions))
+		for _, permission := range entry.Permissions {
+			permissions = append(permissions, fmt.Sprintf("`%s`", permission))
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n",
+			entry.Repository,
+			entry.Provider,
+			entry.Target,
+			entry.AccessLevel,
+			strings.Join(permissions, "<br>"),
+		)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,199 @@
+package report
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/aws"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/google"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/scaleway"
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/stack"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+)
+
+// The access levels of an entry.
+const (
+	// accessLevelMain is the access level of a repository's own project or account.
+	accessLevelMain = "main"
+	// accessLevelFull is the access level of a linked project granted the repository's permissions.
+	accessLevelFull = "full"
+	// accessLevelDefault is the access level of a linked project granted its own and the default permissions.
+	accessLevelDefault = "default"
+	// accessLevelOrganization is the access level of the Scaleway organization.
+	accessLevelOrganization = "organization"
+)
+
+// Entry is the effective permission set of a repository on a target.
+type Entry struct {
+	// Repository is the name of the repository.
+	Repository string `json:"repository"`
+	// Provider is the cloud provider of the target: google, aws, or scaleway.
+	Provider string `json:"provider"`
+	// Target is the Google Cloud project, AWS account, Scaleway project, or Scaleway organization.
+	Target string `json:"target"`
+	// AccessLevel is the access level of the repository on the target: main, full, default, or organization.
+	AccessLevel string `json:"accessLevel"`
+	// Permissions are the effective IAM permissions, or permission sets, including the defaults.
+	Permissions []string `json:"permissions"`
+}
+
+// Build resolves the access matrix of the given repositories.
+// The repositories are resolved as during a deployment, hence references to unconfigured
+// Google Cloud projects, AWS accounts, or Scaleway projects are skipped, or fail in strict mode.
+// The entries are sorted by repository, provider, and target.
+// repositories: The repository configurations.
+// stackConfig: The project configuration of the stack.
+func Build(repositories []*repoConf.Config, stackConfig *stack.Config) ([]Entry, error) {
+	var strict bool
+	if stackConfig.Repositories != nil {
+		strict = defaults.GetOrDefault(stackConfig.Repositories.StrictReferences, false)
+	}
+
+	var entries []Entry
+	var errs []error
+	if stackConfig.Google != nil {
+		googleEntries, gErr := googleAccess(repositories, stackConfig, strict)
+		entries = append(entries, googleEntries...)
+		errs = append(errs, gErr)
+	}
+	if stackConfig.Aws != nil {
+		awsEntries, aErr := awsAccess(repositories, stackConfig, strict)
+		entries = append(entries, awsEntries...)
+		errs = append(errs, aErr)
+	}
+	if stackConfig.Scaleway != nil {
+		scalewayEntries, sErr := scalewayAccess(repositories, stackConfig, strict)
+		entries = append(entries, scalewayEntries...)
+		errs = append(errs, sErr)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return cmp.Or(
+			cmp.Compare(a.Repository, b.Repository),
+			cmp.Compare(a.Provider, b.Provider),
+			cmp.Compare(a.Target, b.Target),
+		)
+	})
+	return entries, nil
+}
+
+// googleAccess resolves the access of the given repositories to Google Cloud projects.
+// repositories: The repository configurations.
+// stackConfig: The project configuration of the stack.
+// strict: Whether to fail on references to unconfigured projects.
+func googleAccess(repositories []*repoConf.Config, stackConfig *stack.Config, strict bool) ([]Entry, error) {
+	projects, err := google.ResolveRepositoryProjects(repositories, stackConfig.Google, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, project := range projects {
+		entries = append(entries, Entry{
+			Repository:  *project.Repository,
+			Provider:    "google",
+			Target:      *project.Name,
+			AccessLevel: accessLevelMain,
+			Permissions: sorted(google.ProjectPermissions(project, *project.Name)),
+		})
+		for linkedProject, linkedConfig := range project.LinkedProjects {
+			entries = append(entries, Entry{
+				Repository:  *project.Repository,
+				Provider:    "google",
+				Target:      linkedProject,
+				AccessLevel: linkedAccessLevel(linkedConfig.AccessLevel),
+				Permissions: sorted(google.ProjectPermissions(project, linkedProject)),
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// awsAccess resolves the access of the given repositories to AWS accounts.
+// repositories: The repository configurations.
+// stackConfig: The project configuration of the stack.
+// strict: Whether to fail on references to unconfigured accounts.
+func awsAccess(repositories []*repoConf.Config, stackConfig *stack.Config, strict bool) ([]Entry, error) {
+	accounts, err := aws.ResolveRepositoryAccounts(repositories, stackConfig.Aws, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, account := range accounts {
+		entries = append(entries, Entry{
+			Repository:  *account.Repository,
+			Provider:    "aws",
+			Target:      *account.ID,
+			AccessLevel: accessLevelMain,
+			Permissions: sorted(account.IAMPermissions),
+		})
+	}
+
+	return entries, nil
+}
+
+// scalewayAccess resolves the access of the given repositories to the Scaleway organization and projects.
+// repositories: The repository configurations.
+// stackConfig: The project configuration of the stack.
+// strict: Whether to fail on references to unconfigured projects.
+func scalewayAccess(repositories []*repoConf.Config, stackConfig *stack.Config, strict bool) ([]Entry, error) {
+	projects, err := scaleway.ResolveRepositoryProjects(repositories, stackConfig.Scaleway, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, project := range projects {
+		entries = append(entries,
+			Entry{
+				Repository:  *project.Repository,
+				Provider:    "scaleway",
+				Target:      defaults.GetOrDefault(project.OrganizationID, accessLevelOrganization),
+				AccessLevel: accessLevelOrganization,
+				Permissions: sorted(scaleway.OrganizationPermissions()),
+			},
+			Entry{
+				Repository:  *project.Repository,
+				Provider:    "scaleway",
+				Target:      *project.Name,
+				AccessLevel: accessLevelMain,
+				Permissions: sorted(scaleway.ProjectPermissions(project, *project.Name)),
+			},
+		)
+		for linkedProject, linkedConfig := range project.LinkedProjects {
+			entries = append(entries, Entry{
+				Repository:  *project.Repository,
+				Provider:    "scaleway",
+				Target:      linkedProject,
+				AccessLevel: linkedAccessLevel(linkedConfig.AccessLevel),
+				Permissions: sorted(scaleway.ProjectPermissions(project, linkedProject)),
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// linkedAccessLevel returns the access level of a linked project, which defaults to 'default'.
+// accessLevel: The configured access level.
+func linkedAccessLevel(accessLevel string) string {
+	if accessLevel == accessLevelFull {
+		return accessLevelFull
+	}
+	return accessLevelDefault
+}
+
+// sorted returns a sorted copy of the given permissions without duplicates.
+// permissions: The permissions.
+func sorted(permissions []string) []string {
+	permissions = slices.Clone(permissions)
+	slices.Sort(permissions)
+	return slices.Compact(permissions)
+}
@@ -1,13 +1,17 @@
 package scaleway
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
+	repositoriesConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
 	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
 	scalewayConf "github.com/muhlba91/github-infrastructure/pkg/model/config/scaleway"
 	"github.com/muhlba91/github-infrastructure/pkg/model/scaleway"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
-	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	scw "github.com/pulumiverse/pulumi-scaleway/sdk/go/scaleway"
 	"github.com/rs/zerolog/log"
@@ -16,31 +20,45 @@ import (
 // Configure sets up Scaleway resources based on the provided configuration.
 // ctx: Pulumi context for resource management.
 // repositories: List of repository configurations.
-// vaultStores: Map of Vault mount configurations.
+// sinks: Map of credential sinks keyed by repository name.
 // scalewayConfig: Scaleway configuration details.
+// repositoriesConfig: Repository configuration details.
 func Configure(ctx *pulumi.Context,
 	repositories []*repoConf.Config,
-	vaultStores map[string]*vault.Mount,
+	sinks map[string]integration.Sink,
 	scalewayConfig *scalewayConf.Config,
-) (map[string][]string, error) {
-	providers := createProviders(ctx, scalewayConfig)
+	repositoriesConfig *repositoriesConf.Config,
+) (map[string][]string, integration.Inventory, error) {
+	googleRepositoryProjects, rpErr := ResolveRepositoryProjects(
+		repositories,
+		scalewayConfig,
+		defaults.GetOrDefault(repositoriesConfig.StrictReferences, false),
+	)
+	if rpErr != nil {
+		log.Err(rpErr).Msg("[scaleway][configure] error resolving Scaleway projects for repositories")
+		return nil, nil, rpErr
+	}
 
-	googleRepositoryProjects := createScalewayRepositoryProjects(repositories, scalewayConfig)
+	providers := createProviders(ctx, scalewayConfig)
 
+	var errs []error
 	projects := make(map[string][]string)
+	inventory := make(integration.Inventory)
 	for _, repositoryProject := range googleRepositoryProjects {
-		pErr := configureProject(
+		details, pErr := configureProject(
 			ctx,
 			repositoryProject,
-			vaultStores[*repositoryProject.Repository],
+			sinks[*repositoryProject.Repository],
 			scalewayConfig,
 			providers[*repositoryProject.Name],
 		)
 		if pErr != nil {
 			log.Err(pErr).
 				Msgf("[scaleway][configure] error configuring Scaleway project for repository: %s", *repositoryProject.Repository)
-			return nil, pErr
+			errs = append(errs, fmt.Errorf("[scaleway][%s] %w", *repositoryProject.Repository, pErr))
+			continue
 		}
+		inventory[*repositoryProject.Repository] = details
 
 		projectRepositoryMapping, prmOk := projects[*repositoryProject.Name]
 		if !prmOk {
@@ -57,7 +75,11 @@ func Configure(ctx *pulumi.Context,
 		}
 	}
 
-	return projects, nil
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	return projects, inventory, nil
 }
 
 // createProviders initializes GCP providers for each project specified in the configuration.
@@ -79,41 +101,39 @@ func createProviders(ctx *pulumi.Context, scalewayConfig *scalewayConf.Config) m
 
 // filterRepositoryByAllowedProjects checks if the repository's Scaleway project
 // is included in the list of allowed projects from the configuration.
+// It returns an error describing the first unconfigured project; linked projects are checked in sorted order.
 // repoAccessPermissionsScaleway: Scaleway access configuration for the repository.
 // scalewayConfig: Scaleway configuration details.
 func filterRepositoryByAllowedProjects(
 	repoAccessPermissionsScaleway repoConf.ScalewayAccessConfig,
 	scalewayConfig *scalewayConf.Config,
-) bool {
-	mainProject := repoAccessPermissionsScaleway.Project
-	if mainProject == nil || scalewayConfig.Projects[*mainProject] == nil {
-		log.Error().Msgf("[scaleway][%v] the repository references an unconfigured project", *mainProject)
-		return false
-	}
-
-	linkedProjects := repoAccessPermissionsScaleway.LinkedProjects
-	if linkedProjects == nil {
-		return true
+) error {
+	mainProject := defaults.GetOrDefault(repoAccessPermissionsScaleway.Project, "")
+	if scalewayConfig.Projects[mainProject] == nil {
+		return fmt.Errorf("the repository references an unconfigured project: %s", mainProject)
 	}
 
-	for project := range linkedProjects {
+	for _, project := range slices.Sorted(maps.Keys(repoAccessPermissionsScaleway.LinkedProjects)) {
 		if scalewayConfig.Projects[project] == nil {
-			log.Error().Msgf("[scaleway][%v] the repository references an unconfigured project", project)
-			return false
+			return fmt.Errorf("the repository references an unconfigured linked project: %s", project)
 		}
 	}
 
-	return true
+	return nil
 }
 
-// createScalewayRepositoryProjects constructs a map of Scaleway repository projects
+// ResolveRepositoryProjects constructs a map of Scaleway repository projects
 // based on the provided repository configurations and Scaleway configuration.
+// Repositories referencing unconfigured projects are skipped, or cause an error in strict mode.
 // repositories: List of repository configurations.
 // scalewayConfig: Scaleway configuration details.
-func createScalewayRepositoryProjects(
+// strict: Whether to return an error for repositories referencing unconfigured projects.
+func ResolveRepositoryProjects(
 	repositories []*repoConf.Config,
 	scalewayConfig *scalewayConf.Config,
-) map[string]*scaleway.RepositoryProject {
+	strict bool,
+) (map[string]*scaleway.RepositoryProject, error) {
+	var errs []error
 	scalewayRepositoryProjects := make(map[string]*scaleway.RepositoryProject)
 	for _, repository := range repositories {
 		repoAccessPermissions := defaults.GetOrDefault(
@@ -125,8 +145,16 @@ func createScalewayRepositoryProjects(
 			repoConf.ScalewayAccessConfig{},
 		)
 
-		if repoAccessPermissionsScaleway.Project != nil && *repoAccessPermissionsScaleway.Project != "" &&
-			filterRepositoryByAllowedProjects(repoAccessPermissionsScaleway, scalewayConfig) {
+		if repoAccessPermissionsScaleway.Project != nil && *repoAccessPermissionsScaleway.Project != "" {
+			if fErr := filterRepositoryByAllowedProjects(repoAccessPermissionsScaleway, scalewayConfig); fErr != nil {
+				if strict {
+					errs = append(errs, fmt.Errorf("[scaleway][%s] %w", repository.Name, fErr))
+				} else {
+					log.Error().Msgf("[scaleway][%s] %s", repository.Name, fErr)
+				}
+				continue
+			}
+
 			project := defaults.GetOrDefault(repoAccessPermissionsScaleway.Project, "")
 			region := defaults.GetOrDefault(
 				repoAccessPermissionsScaleway.Region,
@@ -164,5 +192,9 @@ func createScalewayRepositoryProjects(
 		}
 	}
 
-	return scalewayRepositoryProjects
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return scalewayRepositoryProjects, nil
 }
@@ -0,0 +1,88 @@
+package scaleway
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
+	repositoriesConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	scalewayConf "github.com/muhlba91/github-infrastructure/pkg/model/config/scaleway"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// integrationName is the name of the Scaleway integration.
+const integrationName = "scaleway"
+
+// Integration configures Scaleway IAM applications for repositories.
+type Integration struct {
+	// scalewayConfig contains the Scaleway configuration.
+	scalewayConfig *scalewayConf.Config
+	// repositoriesConfig contains the repositories configuration.
+	repositoriesConfig *repositoriesConf.Config
+}
+
+// NewIntegration creates the Scaleway integration.
+// scalewayConfig: Scaleway configuration details.
+// repositoriesConfig: Repository configuration details.
+func NewIntegration(
+	scalewayConfig *scalewayConf.Config,
+	repositoriesConfig *repositoriesConf.Config,
+) integration.Integration {
+	return &Integration{
+		scalewayConfig:     scalewayConfig,
+		repositoriesConfig: repositoriesConfig,
+	}
+}
+
+// Name returns the name of the integration.
+func (*Integration) Name() string {
+	return integrationName
+}
+
+// Enabled returns whether the given repository references a Scaleway project.
+// repository: The repository configuration.
+func (*Integration) Enabled(repository *repoConf.Config) bool {
+	scaleway := accessConfig(repository)
+	return defaults.GetOrDefault(scaleway.Project, "") != ""
+}
+
+// Configure sets up Scaleway resources for the given repositories.
+// ctx: The Pulumi context for resource management.
+// repositories: The repositories the integration is enabled for.
+// sinks: The credential sinks keyed by repository name.
+func (i *Integration) Configure(
+	ctx *pulumi.Context,
+	repositories []*repoConf.Config,
+	sinks map[string]integration.Sink,
+) (any, integration.Inventory, error) {
+	return Configure(ctx, repositories, sinks, i.scalewayConfig, i.repositoriesConfig)
+}
+
+// Outputs returns the allowed Scaleway projects and the repositories configured for each project.
+// configured: The repositories keyed by project returned by Configure.
+func (i *Integration) Outputs(configured any) map[string]any {
+	return map[string]any{
+		"allowed":    slices.Sorted(maps.Keys(i.scalewayConfig.Projects)),
+		"configured": configured,
+	}
+}
+
+// Flags returns whether Scaleway access is configured for the given repository.
+// repository: The repository configuration.
+func (*Integration) Flags(repository *repoConf.Config) map[string]bool {
+	return map[string]bool{
+		integrationName: accessConfig(repository).Project != nil,
+	}
+}
+
+// accessConfig returns the Scaleway access configuration of the given repository.
+// repository: The repository configuration.
+func accessConfig(repository *repoConf.Config) repoConf.ScalewayAccessConfig {
+	repoAccessPermissions := defaults.GetOrDefault(
+		repository.AccessPermissions,
+		repoConf.AccessPermissionsConfig{},
+	)
+	return defaults.GetOrDefault(repoAccessPermissions.Scaleway, repoConf.ScalewayAccessConfig{})
+}
@@ -1,9 +1,9 @@
 package scaleway
 
 import (
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
 	scalewayConf "github.com/muhlba91/github-infrastructure/pkg/model/config/scaleway"
 	"github.com/muhlba91/github-infrastructure/pkg/model/scaleway"
-	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	scw "github.com/pulumiverse/pulumi-scaleway/sdk/go/scaleway"
 	"github.com/rs/zerolog/log"
@@ -12,26 +12,29 @@ import (
 // configureProject sets up Scaleway project resources based on the provided configuration.
 // ctx: Pulumi context for resource management.
 // project: Scaleway project details.
-// vaultStore: Vault mount where secrets will be stored.
+// sink: The credential sink of the repository.
 // scalewayConfig: Scaleway configuration details.
 // provider: Scaleway provider for resource creation.
 func configureProject(ctx *pulumi.Context,
 	project *scaleway.RepositoryProject,
-	vaultStore *vault.Mount,
+	sink integration.Sink,
 	scalewayConfig *scalewayConf.Config,
 	provider *scw.Provider,
-) error {
-	_, saErr := createProjectIAM(
+) (pulumi.Map, error) {
+	application, saErr := createProjectIAM(
 		ctx,
 		project,
-		vaultStore,
+		sink,
 		scalewayConfig,
 		provider,
 	)
 	if saErr != nil {
 		log.Err(saErr).Msgf("[scaleway][project] error configuring IAM for Scaleway project: %s", *project.Name)
-		return saErr
+		return nil, saErr
 	}
 
-	return nil
+	return pulumi.Map{
+		"project":     pulumi.String(*project.Name),
+		"application": application.Application.ID(),
+	}, nil
 }
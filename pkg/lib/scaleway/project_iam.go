@@ -1,18 +1,16 @@
 package scaleway
 
 import (
-	"encoding/json"
 	"fmt"
 	"slices"
 
-	"github.com/muhlba91/github-infrastructure/pkg/lib/config"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
 	scalewayConf "github.com/muhlba91/github-infrastructure/pkg/model/config/scaleway"
 	scalewayModel "github.com/muhlba91/github-infrastructure/pkg/model/scaleway"
 	"github.com/muhlba91/pulumi-shared-library/pkg/lib/scaleway/iam/policy"
-	"github.com/muhlba91/pulumi-shared-library/pkg/lib/vault/secret"
 	scwmodel "github.com/muhlba91/pulumi-shared-library/pkg/model/scaleway/iam/application"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/scaleway/iam/application"
-	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	scw "github.com/pulumiverse/pulumi-scaleway/sdk/go/scaleway"
 	"github.com/pulumiverse/pulumi-scaleway/sdk/go/scaleway/iam"
@@ -22,12 +20,12 @@ import (
 // createProjectIAM creates IAM roles and service accounts for Continuous Integration in the specified Scaleway project.
 // ctx: Pulumi context for resource management.
 // project: The repository project configuration.
-// vaultStore: Vault mount configuration.
+// sink: The credential sink of the repository.
 // scalewayConfig: Scaleway configuration details.
 // provider: Scaleway provider configured for the specific project.
 func createProjectIAM(ctx *pulumi.Context,
 	project *scalewayModel.RepositoryProject,
-	vaultStore *vault.Mount,
+	sink integration.Sink,
 	scalewayConfig *scalewayConf.Config,
 	provider *scw.Provider,
 ) (*scwmodel.Application, error) {
@@ -61,30 +59,23 @@ func createProjectIAM(ctx *pulumi.Context,
 		return nil, rErr
 	}
 
-	pulumi.All(vaultStore.Path, application.Key.AccessKey, application.Key.SecretKey).ApplyT(func(all []any) error {
-		path, _ := all[0].(string)
-		accessKey, _ := all[1].(string)
-		secretKey, _ := all[2].(string)
-
-		value, _ := json.Marshal(map[string]string{
-			"access_key":      accessKey,
-			"secret_key":      secretKey,
-			"region":          *project.Region,
-			"zone":            *project.Zone,
-			"organization_id": *project.OrganizationID,
-			"project_id":      *scalewayConfig.Projects[*project.Name],
-		})
-
-		_, err := secret.Create(ctx, &secret.CreateOptions{
-			Path:  path,
-			Key:   "scaleway",
-			Value: pulumi.String(value),
-			PulumiOptions: []pulumi.ResourceOption{
-				pulumi.Provider(config.VaultProvider),
-			},
-		})
-		return err
+	sErr := sink.Store(ctx, integration.Credential{
+		Key: "scaleway",
+		Values: pulumi.StringMap{
+			"access_key":      application.Key.AccessKey,
+			"region":          pulumi.String(*project.Region),
+			"zone":            pulumi.String(*project.Zone),
+			"organization_id": pulumi.String(*project.OrganizationID),
+			"project_id":      pulumi.String(*scalewayConfig.Projects[*project.Name]),
+		},
+		Secrets: pulumi.StringMap{
+			"secret_key": application.Key.SecretKey,
+		},
 	})
+	if sErr != nil {
+		log.Err(sErr).Msgf("[scaleway][iam] error storing application key for Scaleway project: %s", *project.Name)
+		return nil, sErr
+	}
 
 	return application, nil
 }
@@ -107,19 +98,12 @@ func createCIPolicy(
 	rules := []iam.PolicyRuleInput{
 		&iam.PolicyRuleArgs{
 			OrganizationId:     pulumi.String(*project.OrganizationID),
-			PermissionSetNames: pulumi.ToStringArray(defaultOrganizationPermissions),
+			PermissionSetNames: pulumi.ToStringArray(OrganizationPermissions()),
 		},
 	}
 
 	for _, projName := range *scalewayProjects {
-		var permissions []string
-		linkedProj, ok := project.LinkedProjects[projName]
-		if projName == *project.Name || (ok && linkedProj.AccessLevel == "full") {
-			permissions = project.IAMPermissions
-		} else {
-			permissions = append([]string{}, linkedProj.IAMPermissions...)
-			permissions = append(permissions, defaultProjectPermissions...)
-		}
+		permissions := ProjectPermissions(project, projName)
 
 		rules = append(rules, &iam.PolicyRuleArgs{
 			ProjectIds: pulumi.StringArray{
@@ -141,9 +125,7 @@ func createCIPolicy(
 			),
 			Rules:         rules,
 			ApplicationID: applicationID,
-			PulumiOptions: []pulumi.ResourceOption{
-				pulumi.Provider(provider),
-			},
+			PulumiOptions: component.WithRepository(ctx, *project.Repository, pulumi.Provider(provider)),
 		},
 	)
 	if polErr != nil {
@@ -181,10 +163,31 @@ func createApplication(
 					*project.Repository,
 				),
 			),
-			PulumiOptions: []pulumi.ResourceOption{
-				pulumi.Provider(provider),
-			},
-			Labels: append(commonLabels(), fmt.Sprintf("repository=%s", *project.Repository)),
+			PulumiOptions: component.WithRepository(ctx, *project.Repository, pulumi.Provider(provider)),
+			Labels:        append(commonLabels(), fmt.Sprintf("repository=%s", *project.Repository)),
 		},
 	)
 }
+
+// OrganizationPermissions returns the permission sets every repository is granted in the Scaleway organization.
+func OrganizationPermissions() []string {
+	return slices.Clone(defaultOrganizationPermissions)
+}
+
+// ProjectPermissions returns the effective permission sets of a repository in the given Scaleway project.
+// The repository's project and linked projects with full access are granted the repository's permissions,
+// other linked projects their own permissions and the default project permissions.
+// project: The repository project configuration.
+// scalewayProject: The name of the Scaleway project.
+func ProjectPermissions(project *scalewayModel.RepositoryProject, scalewayProject string) []string {
+	linkedProj, ok := project.LinkedProjects[scalewayProject]
+	if scalewayProject == *project.Name || (ok && linkedProj.AccessLevel == "full") {
+		return project.IAMPermissions
+	}
+
+	var permissions []string
+	if ok {
+		permissions = append(permissions, linkedProj.IAMPermissions...)
+	}
+	return append(permissions, defaultProjectPermissions...)
+}
@@ -0,0 +1,80 @@
+package sink
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
+	ghSecret "github.com/muhlba91/pulumi-shared-library/pkg/lib/github/actions/secret"
+	"github.com/pulumi/pulumi-github/sdk/v6/go/github"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/rs/zerolog/log"
+)
+
+// githubSink stores credentials as GitHub Actions secrets and variables of a repository.
+type githubSink struct {
+	// repository is the name of the repository.
+	repository string
+	// githubRepository is the GitHub repository resource.
+	githubRepository *github.Repository
+	// variables indicates whether non-sensitive fields are stored as variables instead of secrets.
+	variables bool
+}
+
+// Name returns the name of the GitHub Actions secret or variable of a credential's field, e.g. 'AWS_REGION'.
+// key: The key of the credential.
+// field: The name of the field.
+func Name(key string, field string) string {
+	return strings.ToUpper(strings.ReplaceAll(fmt.Sprintf("%s_%s", key, field), "-", "_"))
+}
+
+// Store stores each field of the credential as a GitHub Actions secret, or non-sensitive fields as variables.
+// ctx: The Pulumi context for resource management.
+// credential: The credential to store.
+func (s *githubSink) Store(ctx *pulumi.Context, credential integration.Credential) error {
+	if s.githubRepository == nil {
+		return fmt.Errorf("no GitHub repository configured for repository: %s", s.repository)
+	}
+
+	secrets := credential.Secrets
+	if !s.variables {
+		secrets = credential.Fields()
+	} else {
+		for _, field := range slices.Sorted(maps.Keys(credential.Values)) {
+			name := Name(credential.Key, field)
+			_, err := github.NewActionsVariable(
+				ctx,
+				fmt.Sprintf("github-variable-%s-%s", s.repository, strings.ToLower(name)),
+				&github.ActionsVariableArgs{
+					Repository:   s.githubRepository.Name,
+					VariableName: pulumi.String(name),
+					Value:        credential.Values[field],
+				},
+				component.WithRepository(ctx, s.repository)...,
+			)
+			if err != nil {
+				log.Err(err).Msgf("[sink][github] error creating GitHub Actions variable %s for repository: %s", name, s.repository)
+				return err
+			}
+		}
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(secrets)) {
+		name := Name(credential.Key, field)
+		_, err := ghSecret.Create(ctx, &ghSecret.CreateOptions{
+			Key:           name,
+			Value:         secrets[field],
+			Repository:    s.githubRepository,
+			PulumiOptions: component.WithRepository(ctx, s.repository),
+		})
+		if err != nil {
+			log.Err(err).Msgf("[sink][github] error creating GitHub Actions secret %s for repository: %s", name, s.repository)
+			return err
+		}
+	}
+
+	return nil
+}
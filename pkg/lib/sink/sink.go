@@ -0,0 +1,63 @@
+package sink
+
+import (
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/pulumi/pulumi-github/sdk/v6/go/github"
+	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
+)
+
+// The kinds of credential sinks.
+const (
+	// Vault stores each credential as a secret in the repository's Vault mount.
+	Vault = "vault"
+	// Secrets stores each field of a credential as a GitHub Actions secret.
+	Secrets = "secrets"
+	// Variables stores the sensitive fields of a credential as GitHub Actions secrets, and all others as variables.
+	Variables = "variables"
+)
+
+// Kind returns the kind of credential sink of the given repository.
+// It defaults to Vault, even if Vault is not available for the repository:
+// credentials are only stored in GitHub if chosen explicitly.
+// repository: The repository configuration.
+func Kind(repository *repoConf.Config) string {
+	if repository.AccessPermissions != nil && repository.AccessPermissions.Sink != nil {
+		return *repository.AccessPermissions.Sink
+	}
+	return Vault
+}
+
+// Create creates the credential sinks of the given repositories.
+// repositories: A slice of repository configurations.
+// vaultStores: The Vault mounts keyed by repository name.
+// githubRepositories: The GitHub repository resources keyed by repository name.
+func Create(
+	repositories []*repoConf.Config,
+	vaultStores map[string]*vault.Mount,
+	githubRepositories map[string]*github.Repository,
+) map[string]integration.Sink {
+	sinks := make(map[string]integration.Sink)
+	for _, repository := range repositories {
+		switch Kind(repository) {
+		case Vault:
+			sinks[repository.Name] = &vaultSink{
+				repository: repository.Name,
+				store:      vaultStores[repository.Name],
+			}
+		case Variables:
+			sinks[repository.Name] = &githubSink{
+				repository:       repository.Name,
+				githubRepository: githubRepositories[repository.Name],
+				variables:        true,
+			}
+		default:
+			sinks[repository.Name] = &githubSink{
+				repository:       repository.Name,
+				githubRepository: githubRepositories[repository.Name],
+			}
+		}
+	}
+
+	return sinks
+}
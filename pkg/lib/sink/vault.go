@@ -0,0 +1,36 @@
+package sink
+
+import (
+	"fmt"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
+	vaultLib "github.com/muhlba91/github-infrastructure/pkg/lib/vault"
+	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// vaultSink stores credentials in the Vault mount of a repository.
+type vaultSink struct {
+	// repository is the name of the repository.
+	repository string
+	// store is the Vault mount of the repository; nil if Vault is not available for the repository.
+	store *vault.Mount
+}
+
+// Store stores the credential as a secret with all of its fields.
+// It fails if Vault is not available for the repository, since the sink is the default one.
+// ctx: The Pulumi context for resource management.
+// credential: The credential to store.
+func (s *vaultSink) Store(ctx *pulumi.Context, credential integration.Credential) error {
+	if s.store == nil {
+		return fmt.Errorf(
+			"no Vault store available for repository %s to store credential '%s'; enable Vault, or choose the '%s' or '%s' sink",
+			s.repository,
+			credential.Key,
+			Secrets,
+			Variables,
+		)
+	}
+
+	return vaultLib.CreateSecret(ctx, s.store, s.repository, credential.Key, pulumi.JSONMarshal(credential.Fields()))
+}
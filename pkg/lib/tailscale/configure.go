@@ -1,15 +1,14 @@
 package tailscale
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 
-	"github.com/muhlba91/github-infrastructure/pkg/lib/config"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
 	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
-	vaultSecret "github.com/muhlba91/pulumi-shared-library/pkg/lib/vault/secret"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
 	tsProvider "github.com/pulumi/pulumi-tailscale/sdk/go/tailscale"
-	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/rs/zerolog/log"
 )
@@ -20,14 +19,16 @@ const maxOauthDescriptionLength = 50
 // Configure sets up Tailscale configurations for the specified repositories.
 // ctx: The Pulumi context for resource management.
 // repositories: A slice of repository configurations.
-// vaultStores: A map of Vault mount configurations.
+// sinks: Map of credential sinks keyed by repository name.
 func Configure(
 	ctx *pulumi.Context,
 	repositories []*repoConf.Config,
-	vaultStores map[string]*vault.Mount,
-) ([]*string, error) {
+	sinks map[string]integration.Sink,
+) ([]*string, integration.Inventory, error) {
 	repos := filterRepositories(repositories)
 
+	var errs []error
+	inventory := make(integration.Inventory)
 	for _, repository := range repos {
 		oauthClient, oErr := tsProvider.NewOauthClient(
 			ctx,
@@ -36,36 +37,41 @@ func Configure(
 				Description: pulumi.String((*repository)[:min(maxOauthDescriptionLength, len(*repository))]),
 				Scopes:      pulumi.ToStringArray([]string{"all"}),
 			},
+			component.WithRepository(ctx, *repository)...,
 		)
 		if oErr != nil {
 			log.Err(oErr).
 				Msgf("[tailscale][configure] error creating Tailscale OAuth client for repository: %s", *repository)
-			return nil, oErr
+			errs = append(errs, fmt.Errorf("[tailscale][%s] %w", *repository, oErr))
+			continue
 		}
 
-		pulumi.All(vaultStores[*repository].Path, oauthClient.ID().ToStringOutput(), oauthClient.Key).
-			ApplyT(func(all []any) error {
-				path, _ := all[0].(string)
-				id, _ := all[1].(string)
-				key, _ := all[2].(string)
+		sErr := sinks[*repository].Store(ctx, integration.Credential{
+			Key: "tailscale",
+			Values: pulumi.StringMap{
+				"oauth_client_id": oauthClient.ID().ToStringOutput(),
+			},
+			Secrets: pulumi.StringMap{
+				"oauth_secret": oauthClient.Key,
+			},
+		})
+		if sErr != nil {
+			log.Err(sErr).
+				Msgf("[tailscale][configure] error storing Tailscale OAuth client for repository: %s", *repository)
+			errs = append(errs, fmt.Errorf("[tailscale][%s] %w", *repository, sErr))
+			continue
+		}
 
-				value, _ := json.Marshal(map[string]string{
-					"oauth_client_id": id,
-					"oauth_secret":    key,
-				})
-				_, _ = vaultSecret.Create(ctx, &vaultSecret.CreateOptions{
-					Path:  path,
-					Key:   "tailscale",
-					Value: pulumi.String(value),
-					PulumiOptions: []pulumi.ResourceOption{
-						pulumi.Provider(config.VaultProvider),
-					},
-				})
-				return nil
-			})
+		inventory[*repository] = pulumi.Map{
+			"clientId": oauthClient.ID(),
+		}
 	}
 
-	return repos, nil
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	return repos, inventory, nil
 }
 
 // filterRepositories filters the given repositories to include only those that we want to create Tailscale configurations for.
@@ -73,14 +79,20 @@ func Configure(
 func filterRepositories(repositories []*repoConf.Config) []*string {
 	var repos []*string
 	for _, repository := range repositories {
-		repoAccessPermissions := defaults.GetOrDefault(
-			repository.AccessPermissions,
-			repoConf.AccessPermissionsConfig{},
-		)
-		if defaults.GetOrDefault(repoAccessPermissions.Tailscale, false) {
+		if enabled(repository) {
 			repos = append(repos, &repository.Name)
 		}
 	}
 
 	return repos
 }
+
+// enabled returns whether Tailscale access is configured for the given repository.
+// repository: The repository configuration.
+func enabled(repository *repoConf.Config) bool {
+	repoAccessPermissions := defaults.GetOrDefault(
+		repository.AccessPermissions,
+		repoConf.AccessPermissionsConfig{},
+	)
+	return defaults.GetOrDefault(repoAccessPermissions.Tailscale, false)
+}
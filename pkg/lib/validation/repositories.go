@@ -0,0 +1,275 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/sink"
+	vaultLib "github.com/muhlba91/github-infrastructure/pkg/lib/vault"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/vault/policy"
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/stack"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+)
+
+// Repositories cross-checks the repository configurations against the stack configuration.
+// It reports duplicate names, references to unconfigured Google Cloud projects, AWS accounts, and Scaleway projects,
+// integrations which store their credentials in Vault while Vault is not available for the repository,
+// invalid scoped Vault roles, secrets shared with unknown repositories, or with or by repositories without Vault,
+// and Vault policies failing the lint.
+// repositories: A slice of repository configurations.
+// stackConfig: The project configuration of the stack.
+func Repositories(repositories []*repoConf.Config, stackConfig *stack.Config) []error {
+	errs := validateNames(repositories)
+
+	names := make(map[string]bool)
+	vaultRepositories := make(map[string]bool)
+	vaultEnabled := stackConfig.Vault != nil && defaults.GetOrDefault(stackConfig.Vault.Enabled, false)
+	for _, repository := range repositories {
+		names[strings.ToLower(repository.Name)] = true
+		vaultRepositories[repository.Name] = vaultAvailable(repository, vaultEnabled)
+	}
+
+	// as during a deployment, shares are only resolved if Vault is enabled for the stack,
+	// while shares with unknown repositories are always reported
+	shares, shErrs := vaultLib.ResolveShares(repositories, func(string) bool {
+		return true
+	})
+	for _, shErr := range shErrs {
+		if vaultEnabled || errors.Is(shErr, vaultLib.ErrUnknownRepository) {
+			errs = append(errs, shErr)
+		}
+	}
+
+	for _, repository := range repositories {
+		accessPermissions := defaults.GetOrDefault(
+			repository.AccessPermissions,
+			repoConf.AccessPermissionsConfig{},
+		)
+
+		errs = append(errs, validateGoogle(repository.Name, accessPermissions.Google, stackConfig)...)
+		errs = append(errs, validateAws(repository.Name, accessPermissions.Aws, stackConfig)...)
+		errs = append(errs, validateScaleway(repository.Name, accessPermissions.Scaleway, stackConfig)...)
+		errs = append(errs, validateVault(repository, accessPermissions, vaultEnabled)...)
+		errs = append(errs, validateVaultRoles(repository.Name, accessPermissions.Vault, names)...)
+		if vaultRepositories[repository.Name] {
+			errs = append(errs, validatePolicies(repository, shares[repository.Name])...)
+		}
+	}
+
+	return errs
+}
+
+// validateNames checks that repository names are unique.
+// The names of custom rulesets are validated with the repository configuration file they are defined in.
+// GitHub treats repository names case-insensitively, hence they are compared case-insensitively.
+// repositories: A slice of repository configurations.
+func validateNames(repositories []*repoConf.Config) []error {
+	var errs []error
+
+	names := make(map[string]string)
+	for _, repository := range repositories {
+		key := strings.ToLower(repository.Name)
+		if existing, ok := names[key]; ok {
+			errs = append(errs, fmt.Errorf("[%s] duplicate repository name (already defined as '%s')",
+				repository.Name, existing))
+			continue
+		}
+		names[key] = repository.Name
+	}
+
+	return errs
+}
+
+// validateGoogle checks that the Google Cloud projects referenced by a repository are configured.
+// name: The name of the repository.
+// google: The Google Cloud access configuration of the repository; may be nil.
+// stackConfig: The project configuration of the stack.
+func validateGoogle(name string, google *repoConf.GoogleAccessConfig, stackConfig *stack.Config) []error {
+	if google == nil || defaults.GetOrDefault(google.Project, "") == "" {
+		return nil
+	}
+
+	var projects []string
+	if stackConfig.Google != nil {
+		projects = stackConfig.Google.Projects
+	}
+
+	var errs []error
+	if !slices.Contains(projects, *google.Project) {
+		errs = append(errs, fmt.Errorf("[%s] unconfigured Google Cloud project: %s", name, *google.Project))
+	}
+	for _, project := range slices.Sorted(maps.Keys(google.LinkedProjects)) {
+		if !slices.Contains(projects, project) {
+			errs = append(errs, fmt.Errorf("[%s] unconfigured linked Google Cloud project: %s", name, project))
+		}
+	}
+
+	return errs
+}
+
+// validateAws checks that the AWS account referenced by a repository is configured.
+// name: The name of the repository.
+// aws: The AWS access configuration of the repository; may be nil.
+// stackConfig: The project configuration of the stack.
+func validateAws(name string, aws *repoConf.AwsAccessConfig, stackConfig *stack.Config) []error {
+	if aws == nil || defaults.GetOrDefault(aws.Account, "") == "" {
+		return nil
+	}
+
+	if stackConfig.Aws == nil || stackConfig.Aws.Account[*aws.Account] == nil {
+		return []error{fmt.Errorf("[%s] unconfigured AWS account: %s", name, *aws.Account)}
+	}
+
+	return nil
+}
+
+// validateScaleway checks that the Scaleway projects referenced by a repository are configured.
+// name: The name of the repository.
+// scaleway: The Scaleway access configuration of the repository; may be nil.
+// stackConfig: The project configuration of the stack.
+func validateScaleway(name string, scaleway *repoConf.ScalewayAccessConfig, stackConfig *stack.Config) []error {
+	if scaleway == nil || defaults.GetOrDefault(scaleway.Project, "") == "" {
+		return nil
+	}
+
+	var projects map[string]*string
+	if stackConfig.Scaleway != nil {
+		projects = stackConfig.Scaleway.Projects
+	}
+
+	var errs []error
+	if projects[*scaleway.Project] == nil {
+		errs = append(errs, fmt.Errorf("[%s] unconfigured Scaleway project: %s", name, *scaleway.Project))
+	}
+	for _, project := range slices.Sorted(maps.Keys(scaleway.LinkedProjects)) {
+		if projects[project] == nil {
+			errs = append(errs, fmt.Errorf("[%s] unconfigured linked Scaleway project: %s", name, project))
+		}
+	}
+
+	return errs
+}
+
+// validateVault checks that Vault is available for repositories with integrations storing credentials in Vault.
+// Vault is only available if it is enabled for the stack, the repository's lifecycle is managed,
+// and Vault access is not disabled for the repository.
+// Repositories without an explicit sink store their credentials in Vault, hence they fail if it is not available.
+// repository: The repository configuration.
+// accessPermissions: The access permissions of the repository.
+// vaultEnabled: Whether Vault is enabled for the stack.
+func validateVault(
+	repository *repoConf.Config,
+	accessPermissions repoConf.AccessPermissionsConfig,
+	vaultEnabled bool,
+) []error {
+	var integrations []string
+	if accessPermissions.GitLab != nil && len(accessPermissions.GitLab.Scopes) > 0 {
+		integrations = append(integrations, "gitlab")
+	}
+	if defaults.GetOrDefault(accessPermissions.Tailscale, false) {
+		integrations = append(integrations, "tailscale")
+	}
+	if accessPermissions.Google != nil && defaults.GetOrDefault(accessPermissions.Google.Project, "") != "" {
+		integrations = append(integrations, "google")
+	}
+	if accessPermissions.Aws != nil && defaults.GetOrDefault(accessPermissions.Aws.Account, "") != "" {
+		integrations = append(integrations, "aws")
+	}
+	if accessPermissions.Scaleway != nil && defaults.GetOrDefault(accessPermissions.Scaleway.Project, "") != "" {
+		integrations = append(integrations, "scaleway")
+	}
+	if len(integrations) == 0 || sink.Kind(repository) != sink.Vault {
+		return nil
+	}
+
+	var reason string
+	switch {
+	case !vaultEnabled:
+		reason = "Vault is disabled for the stack"
+	case !defaults.GetOrDefault(repository.ManageLifecycle, true):
+		reason = "the repository's lifecycle is not managed"
+	case accessPermissions.Vault != nil && !defaults.GetOrDefault(accessPermissions.Vault.Enabled, true):
+		reason = "Vault is disabled for the repository"
+	default:
+		return nil
+	}
+
+	if accessPermissions.Sink == nil {
+		reason += fmt.Sprintf("; choose the '%s' or '%s' sink to store the credentials in GitHub", sink.Secrets, sink.Variables)
+	}
+
+	var errs []error
+	for _, integration := range integrations {
+		errs = append(errs, fmt.Errorf("[%s] integration '%s' requires Vault, but %s", repository.Name, integration, reason))
+	}
+
+	return errs
+}
+
+// validateVaultRoles checks that the scoped Vault roles of a repository have unique names and GitHub Actions secrets,
+// and are bound to any claim.
+// The names of the roles and their policies must not collide with the ones of another repository.
+// name: The name of the repository.
+// vault: The Vault access configuration of the repository; may be nil.
+// names: The lower-cased names of all repositories.
+func validateVaultRoles(name string, vault *repoConf.VaultAccessPermissionsConfig, names map[string]bool) []error {
+	if vault == nil {
+		return nil
+	}
+
+	errs := vaultLib.ValidateRoles(name, vault.Roles)
+	var roles []string
+	for _, role := range vault.Roles {
+		// duplicates are reported with the roles' GitHub Actions secrets
+		if slices.Contains(roles, role.Name) {
+			continue
+		}
+		roles = append(roles, role.Name)
+
+		if len(role.Refs) == 0 && len(role.Environments) == 0 && len(role.Workflows) == 0 {
+			errs = append(errs, fmt.Errorf("[%s] Vault role '%s' is not bound to any ref, environment, or workflow",
+				name, role.Name))
+		}
+		if names[strings.ToLower(fmt.Sprintf("%s-%s", name, role.Name))] {
+			errs = append(errs, fmt.Errorf("[%s] Vault role '%s' collides with the role of repository: %s-%s",
+				name, role.Name, name, role.Name))
+		}
+	}
+
+	return errs
+}
+
+// validatePolicies renders and lints the Vault policies of a repository and its scoped roles.
+// repository: The repository configuration.
+// shares: The shares the repository reads.
+func validatePolicies(repository *repoConf.Config, shares []repoConf.VaultShareConfig) []error {
+	var errs []error
+	options := vaultLib.PolicyOptions(repository, shares)
+	for _, id := range slices.Sorted(maps.Keys(options)) {
+		rendered, err := policy.Render(options[id])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("[%s] error rendering Vault policy github-%s: %w", repository.Name, id, err))
+			continue
+		}
+		for _, lErr := range policy.Lint(fmt.Sprintf("github-%s", id), rendered) {
+			errs = append(errs, fmt.Errorf("[%s] %w", repository.Name, lErr))
+		}
+	}
+
+	return errs
+}
+
+// vaultAvailable returns whether a Vault mount is created for the given repository.
+// Vault is only available if it is enabled for the stack, the repository's lifecycle is managed,
+// and Vault access is not disabled for the repository.
+// repository: The repository configuration.
+// vaultEnabled: Whether Vault is enabled for the stack.
+func vaultAvailable(repository *repoConf.Config, vaultEnabled bool) bool {
+	vaultDisabled := repository.AccessPermissions != nil && repository.AccessPermissions.Vault != nil &&
+		!defaults.GetOrDefault(repository.AccessPermissions.Vault.Enabled, true)
+	return vaultEnabled && defaults.GetOrDefault(repository.ManageLifecycle, true) && !vaultDisabled
+}
@@ -1,17 +1,20 @@
 package vault
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
 	"github.com/muhlba91/github-infrastructure/pkg/lib/config"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/vault/policy"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
-	"github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
 	vaultConf "github.com/muhlba91/github-infrastructure/pkg/model/config/vault"
 	ghSecret "github.com/muhlba91/pulumi-shared-library/pkg/lib/github/actions/secret"
-	vaultSecret "github.com/muhlba91/pulumi-shared-library/pkg/lib/vault/secret"
-	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
 	"github.com/pulumi/pulumi-github/sdk/v6/go/github"
 	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
 	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault/jwt"
@@ -19,158 +22,386 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// defaultTokenTTL is the default time-to-live for Vault tokens issued to GitHub repositories.
-const defaultTokenTTL = 1 * 60 * 60
+const (
+	// defaultTokenTTL is the default time-to-live for Vault tokens issued to GitHub repositories.
+	defaultTokenTTL = 1 * 60 * 60
+	// defaultAuthPath is the default path of the JWT authentication backend of GitHub Actions.
+	defaultAuthPath = "github"
+	// addressSecretName is the name of the GitHub Actions secret containing the Vault address.
+	addressSecretName = "VAULT_ADDR"
+	// roleSecretName is the name of the GitHub Actions secret containing the name of the repository's role.
+	roleSecretName = "VAULT_ROLE"
+	// pathSecretName is the name of the GitHub Actions secret containing the path of the JWT authentication backend.
+	pathSecretName = "VAULT_PATH"
+)
 
-// createAuth creates a JWT authentication backend role in Vault for the given GitHub repository.
+// createAuth creates the JWT authentication backend roles in Vault for the given GitHub repository.
+// The repository's role is bound to the repository only, while its scoped roles are additionally bound
+// to the configured refs, environments, or workflows, and have their own policies.
+// It returns the repository's role, and the scoped roles keyed by their configured name.
 // ctx: The Pulumi context.
 // repository: The repository configuration.
 // mount: The Vault mount where the auth backend is enabled.
 // githubRepository: The GitHub repository resource.
 // repositoriesConfig: The overall repositories configuration.
 // vaultConfig: The Vault configuration.
+// backends: The created JWT authentication backends keyed by their path.
+// shares: The shares the repository reads.
 func createAuth(
 	ctx *pulumi.Context,
-	repository *repository.Config,
+	repository *repoConf.Config,
 	mount *vault.Mount,
 	githubRepository *github.Repository,
 	repositoriesConfig *repositories.Config,
 	vaultConfig *vaultConf.Config,
-) (*jwt.AuthBackendRole, error) {
-	perr := createPolicy(ctx, repository)
+	backends map[string]*jwt.AuthBackend,
+	shares []repoConf.VaultShareConfig,
+) (*jwt.AuthBackendRole, map[string]*jwt.AuthBackendRole, error) {
+	vaultAccessPermissions := vaultPermissions(repository)
+	if rErrs := ValidateRoles(repository.Name, vaultAccessPermissions.Roles); len(rErrs) > 0 {
+		rErr := errors.Join(rErrs...)
+		log.Err(rErr).Msgf("[vault][auth] invalid Vault roles for repository: %s", repository.Name)
+		return nil, nil, rErr
+	}
+
+	auth := authSettings(vaultAccessPermissions.Auth, vaultConfig.GitHubAuth, repositoriesConfig)
+	backend := backends[*auth.Path]
+
+	policies := PolicyOptions(repository, shares)
+	perr := createPolicy(ctx, repository.Name, policies[repository.Name])
 	if perr != nil {
 		log.Err(perr).Msgf("[vault][auth] error creating Vault policy for repository: %s", repository.Name)
-		return nil, perr
+		return nil, nil, perr
 	}
 
-	vaultAddr := repository.AccessPermissions.Vault.Address
+	vaultAddr := vaultAccessPermissions.Address
 	if vaultAddr == nil || *vaultAddr == "" {
 		vaultAddr = vaultConfig.Address
 	}
 
-	jwtRole, abrErr := jwt.NewAuthBackendRole(
-		ctx,
-		fmt.Sprintf("vault-jwt-github-role-%s", repository.Name),
-		&jwt.AuthBackendRoleArgs{
-			Backend:  pulumi.String("github"),
-			RoleType: pulumi.String("jwt"),
-			RoleName: pulumi.String(fmt.Sprintf("github-%s", repository.Name)),
-			TokenPolicies: pulumi.StringArray{
-				pulumi.String(fmt.Sprintf("github-%s", repository.Name)),
-			},
-			TokenTtl: pulumi.Int(defaultTokenTTL),
-			BoundAudiences: pulumi.StringArray{
-				pulumi.String(fmt.Sprintf("https://github.com/%s", *repositoriesConfig.Owner)),
-			},
-			UserClaim: pulumi.String("repository"),
-			BoundClaims: pulumi.StringMap{
-				"repository": pulumi.String(fmt.Sprintf("%s/%s", *repositoriesConfig.Owner, repository.Name)),
-			},
-		},
-		pulumi.Provider(config.VaultProvider),
-	)
+	jwtRole, abrErr := createRole(ctx, repository.Name, repository.Name, nil, auth, backend, repositoriesConfig)
 	if abrErr != nil {
 		log.Err(abrErr).
 			Msgf("[vault][auth] error creating Vault JWT auth backend role for repository: %s", repository.Name)
-		return nil, abrErr
+		return nil, nil, abrErr
+	}
+
+	scopedRoles := make(map[string]*jwt.AuthBackendRole)
+	for _, role := range vaultAccessPermissions.Roles {
+		id := roleID(repository.Name, role.Name)
+		rpErr := createPolicy(ctx, id, policies[id])
+		if rpErr != nil {
+			log.Err(rpErr).
+				Msgf("[vault][auth] error creating Vault policy of role %s for repository: %s", role.Name, repository.Name)
+			return nil, nil, rpErr
+		}
+
+		scopedRole, rErr := createRole(ctx, repository.Name, id, boundClaims(role), auth, backend, repositoriesConfig)
+		if rErr != nil {
+			log.Err(rErr).
+				Msgf("[vault][auth] error creating Vault JWT auth backend role %s for repository: %s",
+					role.Name, repository.Name)
+			return nil, nil, rErr
+		}
+		scopedRoles[role.Name] = scopedRole
 	}
 
-	createSecrets(ctx, mount, jwtRole, githubRepository, vaultAddr)
+	sErr := createSecrets(ctx, repository.Name, mount, jwtRole, scopedRoles, githubRepository, vaultAddr, *auth.Path)
+	if sErr != nil {
+		return nil, nil, sErr
+	}
 
-	return jwtRole, nil
+	return jwtRole, scopedRoles, nil
 }
 
-// createPolicy creates a Vault policy for the given GitHub repository.
-// ctx: The Pulumi context.
+// vaultPermissions returns the Vault access permissions of the given repository, or empty ones if none are configured.
 // repository: The repository configuration.
-func createPolicy(ctx *pulumi.Context, repository *repository.Config) error {
-	additionalPaths := []map[string]string{}
-	if repository.AccessPermissions != nil && repository.AccessPermissions.Vault != nil &&
-		repository.AccessPermissions.Vault.AdditionalMounts != nil {
-		for _, mount := range repository.AccessPermissions.Vault.AdditionalMounts {
-			permissions := []string{}
-			for _, permission := range mount.Permissions {
-				permissions = append(permissions, fmt.Sprintf(`"%s"`, permission))
-			}
-			additionalPaths = append(additionalPaths, map[string]string{
-				"path":        mount.Path,
-				"permissions": strings.Join(permissions, ", "),
-			})
+func vaultPermissions(repository *repoConf.Config) repoConf.VaultAccessPermissionsConfig {
+	if repository.AccessPermissions == nil || repository.AccessPermissions.Vault == nil {
+		return repoConf.VaultAccessPermissionsConfig{}
+	}
+	return *repository.AccessPermissions.Vault
+}
+
+// authSettings returns the JWT authentication settings of a repository's roles.
+// The settings of the repository take precedence over the ones of the stack, followed by the defaults.
+// repository: The settings of the repository; may be nil.
+// stack: The settings of the stack; may be nil.
+// repositoriesConfig: The overall repositories configuration.
+func authSettings(
+	repository *vaultConf.GitHubAuthConfig,
+	stack *vaultConf.GitHubAuthConfig,
+	repositoriesConfig *repositories.Config,
+) vaultConf.GitHubAuthConfig {
+	defaultPath := defaultAuthPath
+	defaultTTL := defaultTokenTTL
+	auth := vaultConf.GitHubAuthConfig{
+		Path:      &defaultPath,
+		Audiences: []string{fmt.Sprintf("https://github.com/%s", *repositoriesConfig.Owner)},
+		TokenTTL:  &defaultTTL,
+	}
+	for _, settings := range []*vaultConf.GitHubAuthConfig{stack, repository} {
+		if settings == nil {
+			continue
+		}
+		if defaults.GetOrDefault(settings.Path, "") != "" {
+			auth.Path = settings.Path
+		}
+		if len(settings.Audiences) > 0 {
+			auth.Audiences = settings.Audiences
+		}
+		if settings.TokenTTL != nil {
+			auth.TokenTTL = settings.TokenTTL
+		}
+		if settings.TokenMaxTTL != nil {
+			auth.TokenMaxTTL = settings.TokenMaxTTL
+		}
+		if settings.TokenType != nil {
+			auth.TokenType = settings.TokenType
+		}
+		if len(settings.BoundCIDRs) > 0 {
+			auth.BoundCIDRs = settings.BoundCIDRs
 		}
 	}
 
-	policy, prError := template.Render("assets/vault/policy.hcl.tpl", map[string]any{
-		"repository":      repository.Name,
-		"additionalPaths": additionalPaths,
-	})
+	return auth
+}
+
+// boundClaims returns the GitHub Actions claims a scoped role is bound to in addition to the repository.
+// Multiple values of a claim are alternatives, while all claims must match.
+// role: The scoped role configuration.
+func boundClaims(role repoConf.VaultRoleConfig) map[string]string {
+	claims := make(map[string]string)
+	if len(role.Refs) > 0 {
+		claims["ref"] = strings.Join(role.Refs, ",")
+	}
+	if len(role.Environments) > 0 {
+		claims["environment"] = strings.Join(role.Environments, ",")
+	}
+	if len(role.Workflows) > 0 {
+		claims["job_workflow_ref"] = strings.Join(role.Workflows, ",")
+	}
+	return claims
+}
+
+// createRole creates a JWT authentication backend role bound to the given GitHub repository and claims.
+// The role is granted the policy of the same name; claims may contain glob patterns.
+// ctx: The Pulumi context.
+// repository: The name of the repository.
+// id: The repository name, suffixed with the name of a scoped role; the role is named 'github-<id>'.
+// claims: The claims the role is bound to in addition to the repository.
+// auth: The JWT authentication settings of the role.
+// backend: The JWT authentication backend of the role; nil if it is not created by this program.
+// repositoriesConfig: The overall repositories configuration.
+func createRole(
+	ctx *pulumi.Context,
+	repository string,
+	id string,
+	claims map[string]string,
+	auth vaultConf.GitHubAuthConfig,
+	backend *jwt.AuthBackend,
+	repositoriesConfig *repositories.Config,
+) (*jwt.AuthBackendRole, error) {
+	boundClaims := pulumi.StringMap{
+		"repository": pulumi.String(fmt.Sprintf("%s/%s", *repositoriesConfig.Owner, repository)),
+	}
+	for claim, value := range claims {
+		boundClaims[claim] = pulumi.String(value)
+	}
+	var boundClaimsType pulumi.StringPtrInput
+	if len(claims) > 0 {
+		boundClaimsType = pulumi.String("glob")
+	}
+
+	opts := []pulumi.ResourceOption{pulumi.Provider(config.VaultProvider)}
+	if backend != nil {
+		opts = append(opts, pulumi.DependsOn([]pulumi.Resource{backend}))
+	}
+
+	name := fmt.Sprintf("github-%s", id)
+	return jwt.NewAuthBackendRole(
+		ctx,
+		fmt.Sprintf("vault-jwt-github-role-%s", id),
+		&jwt.AuthBackendRoleArgs{
+			Backend:  pulumi.String(*auth.Path),
+			RoleType: pulumi.String("jwt"),
+			RoleName: pulumi.String(name),
+			TokenPolicies: pulumi.StringArray{
+				pulumi.String(name),
+			},
+			TokenTtl:        pulumi.IntPtrFromPtr(auth.TokenTTL),
+			TokenMaxTtl:     pulumi.IntPtrFromPtr(auth.TokenMaxTTL),
+			TokenType:       pulumi.StringPtrFromPtr(auth.TokenType),
+			TokenBoundCidrs: pulumi.ToStringArray(auth.BoundCIDRs),
+			BoundAudiences:  pulumi.ToStringArray(auth.Audiences),
+			UserClaim:       pulumi.String("repository"),
+			BoundClaims:     boundClaims,
+			BoundClaimsType: boundClaimsType,
+		},
+		component.WithRepository(ctx, repository, opts...)...,
+	)
+}
+
+// createPolicy creates a Vault policy for the given GitHub repository.
+// The policy is linted before it is created, hence invalid policies fail the deployment.
+// ctx: The Pulumi context.
+// id: The repository name, suffixed with the name of a scoped role; the policy is named 'github-<id>'.
+// opts: The rules of the policy.
+func createPolicy(ctx *pulumi.Context, id string, opts *policy.Options) error {
+	name := fmt.Sprintf("github-%s", id)
+	rendered, prError := policy.Render(opts)
 	if prError != nil {
-		log.Err(prError).Msgf("[vault][auth] error rendering Vault policy template for repository: %s", repository.Name)
+		log.Err(prError).Msgf("[vault][auth] error rendering Vault policy template for repository: %s", opts.Repository)
 		return prError
 	}
+	if lErrs := policy.Lint(name, rendered); len(lErrs) > 0 {
+		lErr := errors.Join(lErrs...)
+		log.Err(lErr).Msgf("[vault][auth] invalid Vault policy %s for repository: %s", name, opts.Repository)
+		return lErr
+	}
 
-	_, polErr := vault.NewPolicy(ctx, fmt.Sprintf("vault-policy-github-%s", repository.Name), &vault.PolicyArgs{
-		Name:   pulumi.String(fmt.Sprintf("github-%s", repository.Name)),
-		Policy: pulumi.String(policy),
-	}, pulumi.Provider(config.VaultProvider))
+	_, polErr := vault.NewPolicy(ctx, fmt.Sprintf("vault-policy-%s", name), &vault.PolicyArgs{
+		Name:   pulumi.String(name),
+		Policy: pulumi.String(rendered),
+	}, component.WithRepository(ctx, opts.Repository, pulumi.Provider(config.VaultProvider))...)
 	if polErr != nil {
-		log.Err(polErr).Msgf("[vault][auth] error creating Vault policy for repository: %s", repository.Name)
+		log.Err(polErr).Msgf("[vault][auth] error creating Vault policy %s for repository: %s", name, opts.Repository)
 		return polErr
 	}
 
 	return nil
 }
 
+// PolicyOptions returns the rules of the policies of the given repository's roles keyed by their ID,
+// i.e. the repository name, suffixed with the name of a scoped role.
+// The policy of a scoped role contains the additional mounts of the repository and the role.
+// All policies grant read access to the secrets shared with the repository.
+// repository: The repository configuration.
+// shares: The shares the repository reads.
+func PolicyOptions(repository *repoConf.Config, shares []repoConf.VaultShareConfig) map[string]*policy.Options {
+	vaultAccessPermissions := vaultPermissions(repository)
+	paths := slices.Concat(vaultAccessPermissions.Paths, sharedPaths(shares))
+	options := map[string]*policy.Options{
+		repository.Name: {
+			Repository:       repository.Name,
+			AdditionalMounts: vaultAccessPermissions.AdditionalMounts,
+			Paths:            paths,
+			Templates:        vaultAccessPermissions.PolicyTemplates,
+		},
+	}
+	for _, role := range vaultAccessPermissions.Roles {
+		options[roleID(repository.Name, role.Name)] = &policy.Options{
+			Repository:       repository.Name,
+			AdditionalMounts: slices.Concat(vaultAccessPermissions.AdditionalMounts, role.AdditionalMounts),
+			Paths:            paths,
+			Templates:        vaultAccessPermissions.PolicyTemplates,
+		}
+	}
+
+	return options
+}
+
+// roleID returns the ID of a scoped role of the given repository, which its role and policy names are derived from.
+// repository: The name of the repository.
+// role: The configured name of the scoped role.
+func roleID(repository string, role string) string {
+	return fmt.Sprintf("%s-%s", repository, role)
+}
+
 // createSecrets creates the necessary secrets in Vault and GitHub Actions for the given repository.
 // ctx: The Pulumi context.
+// repository: The name of the repository.
 // mount: The Vault mount where the auth backend is enabled.
 // jwtRole: The JWT authentication backend role in Vault.
+// scopedRoles: The scoped JWT authentication backend roles in Vault keyed by their configured name.
 // githubRepository: The GitHub repository resource.
 // vaultAddr: The address of the Vault server.
+// authPath: The path of the JWT authentication backend.
 func createSecrets(
 	ctx *pulumi.Context,
+	repository string,
 	mount *vault.Mount,
 	jwtRole *jwt.AuthBackendRole,
+	scopedRoles map[string]*jwt.AuthBackendRole,
 	githubRepository *github.Repository,
 	vaultAddr *string,
-) {
-	pulumi.All(mount.Path, jwtRole.RoleName).ApplyT(func(all []any) error {
-		path, _ := all[0].(string)
-		roleName, _ := all[1].(string)
-
-		value, _ := json.Marshal(map[string]string{
-			"address": *vaultAddr,
-			"role":    roleName,
-			"path":    "github",
-		})
-
-		_, _ = vaultSecret.Create(ctx, &vaultSecret.CreateOptions{
-			Path:  path,
-			Key:   "vault",
-			Value: pulumi.String(value),
-			PulumiOptions: []pulumi.ResourceOption{
-				pulumi.Provider(config.VaultProvider),
-				pulumi.DependsOn([]pulumi.Resource{
-					mount,
-				}),
-			},
-		})
-
-		return nil
-	})
+	authPath string,
+) error {
+	err := CreateSecret(ctx, mount, repository, "vault", pulumi.JSONMarshal(pulumi.StringMap{
+		"address": pulumi.String(*vaultAddr),
+		"role":    jwtRole.RoleName,
+		"path":    pulumi.String(authPath),
+	}))
+	if err != nil {
+		log.Err(err).Msgf("[vault][auth] error creating Vault secret for repository: %s", repository)
+		return err
+	}
 
 	ghSecret.Create(ctx, &ghSecret.CreateOptions{
-		Key:        "VAULT_ADDR",
-		Value:      pulumi.String(*vaultAddr),
-		Repository: githubRepository,
+		Key:           addressSecretName,
+		Value:         pulumi.String(*vaultAddr),
+		Repository:    githubRepository,
+		PulumiOptions: component.WithRepository(ctx, repository),
 	})
 	ghSecret.Create(ctx, &ghSecret.CreateOptions{
-		Key:        "VAULT_ROLE",
-		Value:      jwtRole.RoleName,
-		Repository: githubRepository,
+		Key:           roleSecretName,
+		Value:         jwtRole.RoleName,
+		Repository:    githubRepository,
+		PulumiOptions: component.WithRepository(ctx, repository),
 	})
 	ghSecret.Create(ctx, &ghSecret.CreateOptions{
-		Key:        "VAULT_PATH",
-		Value:      pulumi.String("github"),
-		Repository: githubRepository,
+		Key:           pathSecretName,
+		Value:         pulumi.String(authPath),
+		Repository:    githubRepository,
+		PulumiOptions: component.WithRepository(ctx, repository),
 	})
+	for _, name := range slices.Sorted(maps.Keys(scopedRoles)) {
+		ghSecret.Create(ctx, &ghSecret.CreateOptions{
+			Key:           RoleSecretName(name),
+			Value:         scopedRoles[name].RoleName,
+			Repository:    githubRepository,
+			PulumiOptions: component.WithRepository(ctx, repository),
+		})
+	}
+
+	return nil
+}
+
+// ValidateRoles checks that the scoped roles of the given repository have unique names, and that the GitHub Actions
+// secrets their names are published as neither collide with each other, nor with the secrets of the repository's role.
+// Names differing only in case, or in '-' and '_', are published as the same secret.
+// repository: The name of the repository.
+// roles: The scoped role configurations of the repository.
+func ValidateRoles(repository string, roles []repoConf.VaultRoleConfig) []error {
+	secrets := map[string]string{
+		addressSecretName: "",
+		roleSecretName:    "",
+		pathSecretName:    "",
+	}
+
+	var errs []error
+	for _, role := range roles {
+		secret := RoleSecretName(role.Name)
+		other, exists := secrets[secret]
+		switch {
+		case !exists:
+			secrets[secret] = role.Name
+		case other == role.Name:
+			errs = append(errs, fmt.Errorf("[%s] duplicate Vault role name: %s", repository, role.Name))
+		case other == "":
+			errs = append(errs, fmt.Errorf("[%s] Vault role '%s' collides with the GitHub Actions secret of the repository's role: %s",
+				repository, role.Name, secret))
+		default:
+			errs = append(errs, fmt.Errorf("[%s] Vault roles '%s' and '%s' are both published as GitHub Actions secret: %s",
+				repository, other, role.Name, secret))
+		}
+	}
+
+	return errs
+}
+
+// RoleSecretName returns the name of the GitHub Actions secret containing the name of a scoped role, e.g. 'VAULT_ROLE_DEPLOY'.
+// role: The configured name of the scoped role.
+func RoleSecretName(role string) string {
+	return strings.ToUpper(strings.ReplaceAll(fmt.Sprintf("VAULT_ROLE_%s", role), "-", "_"))
 }
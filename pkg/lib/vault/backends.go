@@ -0,0 +1,61 @@
+package vault
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/config"
+	vaultConf "github.com/muhlba91/github-infrastructure/pkg/model/config/vault"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault/jwt"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/rs/zerolog/log"
+)
+
+// githubActionsIssuer is the OIDC issuer of GitHub Actions.
+const githubActionsIssuer = "https://token.actions.githubusercontent.com"
+
+// configureAuthBackends creates the configured JWT authentication backends.
+// It returns the backends keyed by their path; backends which are not configured are expected to exist.
+// ctx: The Pulumi context.
+// vaultConfig: The Vault configuration.
+func configureAuthBackends(
+	ctx *pulumi.Context,
+	vaultConfig *vaultConf.Config,
+) (map[string]*jwt.AuthBackend, error) {
+	backends := make(map[string]*jwt.AuthBackend)
+	var errs []error
+	for i, backendConfig := range vaultConfig.AuthBackends {
+		path := defaults.GetOrDefault(backendConfig.Path, "")
+		if path == "" {
+			errs = append(errs, fmt.Errorf("[vault][authBackends] backend %d: missing required field 'path'", i))
+			continue
+		}
+		if _, ok := backends[path]; ok {
+			errs = append(errs, fmt.Errorf("[vault][authBackends] duplicate backend path: %s", path))
+			continue
+		}
+
+		discoveryURL := defaults.GetOrDefault(backendConfig.OIDCDiscoveryURL, githubActionsIssuer)
+		backend, err := jwt.NewAuthBackend(ctx, fmt.Sprintf("vault-jwt-auth-backend-%s", path), &jwt.AuthBackendArgs{
+			Path:             pulumi.String(path),
+			Type:             pulumi.String("jwt"),
+			Description:      pulumi.StringPtrFromPtr(backendConfig.Description),
+			OidcDiscoveryUrl: pulumi.String(discoveryURL),
+			BoundIssuer:      pulumi.String(defaults.GetOrDefault(backendConfig.BoundIssuer, discoveryURL)),
+			DefaultRole:      pulumi.StringPtrFromPtr(backendConfig.DefaultRole),
+		}, pulumi.Provider(config.VaultProvider))
+		if err != nil {
+			log.Err(err).Msgf("[vault][auth] error creating Vault JWT auth backend: %s", path)
+			errs = append(errs, fmt.Errorf("[vault][authBackends][%s] %w", path, err))
+			continue
+		}
+		backends[path] = backend
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return backends, nil
+}
@@ -0,0 +1,137 @@
+package policy
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// capabilities are the capabilities known to Vault.
+//
+//nolint:gochecknoglobals // lookup table of the known capabilities
+var capabilities = []string{
+	"create", "read", "update", "patch", "delete", "list", "sudo", "deny", "subscribe", "recover",
+}
+
+// broadPrefixes are the path prefixes which must not be granted with a glob, e.g. '*' or 'sys/*'.
+//
+//nolint:gochecknoglobals // lookup table of the overly broad path prefixes
+var broadPrefixes = []string{"", "sys/", "auth/", "identity/"}
+
+// Lint parses the given Vault policy and validates its rules.
+// It rejects syntax errors, unknown attributes and capabilities, and overly broad paths like '*' or 'sys/*'.
+// name: The name of the policy used in the reported errors.
+// policy: The rendered policy.
+func Lint(name string, policy string) []error {
+	file, diags := hclsyntax.ParseConfig([]byte(policy), name, hcl.InitialPos)
+	if diags.HasErrors() {
+		return []error{fmt.Errorf("[%s] invalid policy: %w", name, diags)}
+	}
+	body, _ := file.Body.(*hclsyntax.Body)
+
+	var errs []error
+	for _, attribute := range slices.Sorted(maps.Keys(body.Attributes)) {
+		errs = append(errs, fmt.Errorf("[%s] unknown attribute: %s", name, attribute))
+	}
+	for _, block := range body.Blocks {
+		if block.Type != "path" || len(block.Labels) != 1 {
+			errs = append(errs, fmt.Errorf("[%s] %s: unknown block '%s'; expected 'path \"<path>\"'",
+				name, block.DefRange().String(), block.Type))
+			continue
+		}
+		errs = append(errs, lintPath(name, block)...)
+	}
+
+	return errs
+}
+
+// lintPath validates a single path rule of a policy.
+// Overly broad paths are only reported if they grant any capability, i.e. they are not denied.
+// name: The name of the policy used in the reported errors.
+// block: The path block.
+func lintPath(name string, block *hclsyntax.Block) []error {
+	path := block.Labels[0]
+	var errs []error
+	var granted []string
+	for _, attributeName := range slices.Sorted(maps.Keys(block.Body.Attributes)) {
+		expr := block.Body.Attributes[attributeName].Expr
+		switch attributeName {
+		case "capabilities":
+			values, err := stringList(expr)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("[%s] path '%s': invalid capabilities: %w", name, path, err))
+				continue
+			}
+			for _, capability := range values {
+				if !slices.Contains(capabilities, capability) {
+					errs = append(errs, fmt.Errorf("[%s] path '%s': unknown capability: %s", name, path, capability))
+				}
+				if capability != "deny" {
+					granted = append(granted, capability)
+				}
+			}
+		case "required_parameters":
+			if _, err := stringList(expr); err != nil {
+				errs = append(errs, fmt.Errorf("[%s] path '%s': invalid required_parameters: %w", name, path, err))
+			}
+		case "allowed_parameters", "denied_parameters", "min_wrapping_ttl", "max_wrapping_ttl":
+		default:
+			errs = append(errs, fmt.Errorf("[%s] path '%s': unknown attribute: %s", name, path, attributeName))
+		}
+	}
+	if _, ok := block.Body.Attributes["capabilities"]; !ok {
+		errs = append(errs, fmt.Errorf("[%s] path '%s': missing capabilities", name, path))
+	}
+	if len(granted) > 0 && broad(path) {
+		errs = append(errs, fmt.Errorf("[%s] path '%s' is overly broad", name, path))
+	}
+
+	return errs
+}
+
+// stringList evaluates the given expression as a static list of strings.
+// expr: The expression to evaluate.
+func stringList(expr hcl.Expression) ([]string, error) {
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	if !value.Type().IsTupleType() && !value.Type().IsListType() {
+		return nil, fmt.Errorf("expected a list of strings, got %s", value.Type().FriendlyName())
+	}
+
+	var values []string
+	for it := value.ElementIterator(); it.Next(); {
+		_, element := it.Element()
+		if element.IsNull() || element.Type() != cty.String {
+			return nil, fmt.Errorf("expected a list of strings, got an element of %s", element.Type().FriendlyName())
+		}
+		values = append(values, element.AsString())
+	}
+
+	return values, nil
+}
+
+// broad returns whether the given path grants access to a glob of an overly broad prefix, e.g. '*', '+/*', or 'sys/*'.
+// path: The path of the rule.
+func broad(path string) bool {
+	if !strings.HasSuffix(path, "*") {
+		return false
+	}
+
+	segments := strings.Split(strings.TrimSuffix(path, "*"), "/")
+	for len(segments) > 0 && (segments[len(segments)-1] == "" || segments[len(segments)-1] == "+") {
+		segments = segments[:len(segments)-1]
+	}
+	prefix := strings.Join(segments, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	return slices.Contains(broadPrefixes, prefix)
+}
@@ -0,0 +1,117 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// templatePath is the path of the policy template of every repository.
+	templatePath = "assets/vault/policy.hcl.tpl"
+	// templatesDir is the directory containing the named policy templates.
+	templatesDir = "assets/vault/policies"
+)
+
+// templateName matches the names of policy templates, hence they cannot point outside of the templates directory.
+//
+//nolint:gochecknoglobals // compiled regular expression
+var templateName = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// Options defines the rules of a repository's policy.
+type Options struct {
+	// Repository is the name of the repository.
+	Repository string
+	// AdditionalMounts are the additional mounts the policy grants access to.
+	AdditionalMounts []repoConf.VaultAdditionalMountAccessPermissionsConfig
+	// Paths are the additional paths the policy grants access to.
+	Paths []repoConf.VaultPolicyPathConfig
+	// Templates are the names of the policy templates in 'assets/vault/policies/' appended to the policy.
+	Templates []string
+}
+
+// Render renders the policy of a repository from its template, followed by the named policy templates.
+// Every template is rendered with the repository name, the additional mounts, and the additional paths.
+// The paths, permissions, capabilities, and parameters are passed as HCL string literals.
+// opts: The rules of the policy.
+func Render(opts *Options) (string, error) {
+	additionalPaths := []map[string]string{}
+	for _, mount := range opts.AdditionalMounts {
+		additionalPaths = append(additionalPaths, map[string]string{
+			"path":        literal(mount.Path + "/*"),
+			"permissions": quote(mount.Permissions),
+		})
+	}
+	paths := []map[string]string{}
+	for _, path := range opts.Paths {
+		paths = append(paths, map[string]string{
+			"path":               literal(path.Path),
+			"capabilities":       quote(path.Capabilities),
+			"requiredParameters": quote(path.RequiredParameters),
+		})
+	}
+	data := map[string]any{
+		"repository":      opts.Repository,
+		"additionalPaths": additionalPaths,
+		"paths":           paths,
+	}
+
+	policy, err := template.Render(templatePath, data)
+	if err != nil {
+		return "", err
+	}
+	policies := []string{strings.TrimRight(policy, "\n")}
+	for _, name := range opts.Templates {
+		file, fErr := templateFile(name)
+		if fErr != nil {
+			return "", fErr
+		}
+		rendered, tErr := template.Render(file, data)
+		if tErr != nil {
+			return "", fmt.Errorf("policy template '%s': %w", name, tErr)
+		}
+		policies = append(policies, strings.TrimRight(rendered, "\n"))
+	}
+
+	return strings.Join(policies, "\n") + "\n", nil
+}
+
+// templateFile returns the file of the named policy template.
+// It fails if the name is invalid, or the template does not exist.
+// name: The name of the policy template.
+func templateFile(name string) (string, error) {
+	if !templateName.MatchString(name) {
+		return "", fmt.Errorf("invalid policy template name '%s'; expected %s", name, templateName)
+	}
+	file := fmt.Sprintf("%s/%s.hcl.tpl", templatesDir, name)
+	if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("unknown policy template '%s'; expected a template in %s", name, templatesDir)
+	}
+	return file, nil
+}
+
+// quote returns the given values as a comma-separated list of HCL strings.
+// values: The values to quote.
+func quote(values []string) string {
+	quoted := []string{}
+	for _, value := range values {
+		quoted = append(quoted, literal(value))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// literal returns the given value as an HCL string literal.
+// Quotes, backslashes, and control characters are escaped, as are the template sequences '${' and '%{',
+// hence values cannot alter the structure of the policy.
+// value: The value to quote.
+func literal(value string) string {
+	return string(hclwrite.TokensForValue(cty.StringVal(value)).Bytes())
+}
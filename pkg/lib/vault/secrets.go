@@ -0,0 +1,50 @@
+package vault
+
+import (
+	"fmt"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/config"
+	vaultSecret "github.com/muhlba91/pulumi-shared-library/pkg/lib/vault/secret"
+	"github.com/pulumi/pulumi-vault/sdk/v7/go/vault"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// StorePath returns the path of the Vault store of the given repository.
+// repository: The name of the repository.
+func StorePath(repository string) string {
+	return fmt.Sprintf("github-%s", repository)
+}
+
+// CreateSecret stores a secret in the Vault store of the given repository.
+// The path of the store is derived from the repository name, hence the secret can be registered
+// before the store's outputs are known.
+// ctx: The Pulumi context.
+// store: The Vault store of the repository; may be nil if no store is configured.
+// repository: The name of the repository.
+// key: The key of the secret.
+// value: The value of the secret.
+func CreateSecret(
+	ctx *pulumi.Context,
+	store *vault.Mount,
+	repository string,
+	key string,
+	value pulumi.StringInput,
+) error {
+	if store == nil {
+		return fmt.Errorf("no Vault store configured for repository: %s", repository)
+	}
+
+	_, err := vaultSecret.Create(ctx, &vaultSecret.CreateOptions{
+		Path:  StorePath(repository),
+		Key:   key,
+		Value: value,
+		PulumiOptions: component.WithRepository(
+			ctx,
+			repository,
+			pulumi.Provider(config.VaultProvider),
+			pulumi.DependsOn([]pulumi.Resource{store}),
+		),
+	})
+	return err
+}
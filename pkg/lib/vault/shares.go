@@ -0,0 +1,106 @@
+package vault
+
+import (
+	"errors"
+	"fmt"
+
+	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+)
+
+var (
+	// ErrUnknownRepository is the error of shares with a repository which is not configured.
+	ErrUnknownRepository = errors.New("unknown repository")
+	// ErrVaultUnavailable is the error of shares with a repository which is unmanaged, or has Vault disabled.
+	ErrVaultUnavailable = errors.New("repository is unmanaged or has Vault disabled")
+	// ErrStoreFailed is the error of shares with a repository whose Vault store could not be created.
+	ErrStoreFailed = errors.New("the Vault store of the repository could not be created")
+)
+
+// ResolveShares resolves the secrets shared between repositories via 'readFrom' and 'shareWith'.
+// It returns the shares each repository reads keyed by the name of the reading repository,
+// and the errors of all shares whose reading or sharing repository has no Vault mount,
+// wrapping ErrUnknownRepository, ErrVaultUnavailable, or ErrStoreFailed.
+// repositories: A slice of repository configurations.
+// created: Returns whether the Vault store of the repository of the given name was created.
+func ResolveShares(
+	repositories []*repoConf.Config,
+	created func(repository string) bool,
+) (map[string][]repoConf.VaultShareConfig, []error) {
+	managed := make(map[string]bool)
+	for _, repository := range repositories {
+		managed[repository.Name] = vaultManaged(repository)
+	}
+	// available returns nil if the Vault mount of the repository of the given name exists, and why not otherwise
+	available := func(repository string) error {
+		vManaged, known := managed[repository]
+		switch {
+		case !known:
+			return ErrUnknownRepository
+		case !vManaged:
+			return ErrVaultUnavailable
+		case !created(repository):
+			return ErrStoreFailed
+		default:
+			return nil
+		}
+	}
+
+	shares := make(map[string][]repoConf.VaultShareConfig)
+	var errs []error
+	for _, repository := range repositories {
+		vaultAccessPermissions := vaultPermissions(repository)
+
+		for _, share := range vaultAccessPermissions.ReadFrom {
+			if err := available(share.Repository); err != nil {
+				errs = append(errs, fmt.Errorf("[vault][%s] cannot read secrets from repository %s: %w",
+					repository.Name, share.Repository, err))
+				continue
+			}
+			shares[repository.Name] = append(shares[repository.Name], share)
+		}
+
+		for _, share := range vaultAccessPermissions.ShareWith {
+			if err := available(repository.Name); err != nil {
+				errs = append(errs, fmt.Errorf("[vault][%s] cannot share secrets of the repository with %s: %w",
+					repository.Name, share.Repository, err))
+				continue
+			}
+			if err := available(share.Repository); err != nil {
+				errs = append(errs, fmt.Errorf("[vault][%s] cannot share secrets with repository %s: %w",
+					repository.Name, share.Repository, err))
+				continue
+			}
+			shares[share.Repository] = append(shares[share.Repository], repoConf.VaultShareConfig{
+				Repository: repository.Name,
+				Secrets:    share.Secrets,
+			})
+		}
+	}
+
+	return shares, errs
+}
+
+// sharedPaths returns the policy paths granting read access to the secrets of the given shares.
+// All secrets of a repository are shared if no secrets are given.
+// shares: The shares a repository reads.
+func sharedPaths(shares []repoConf.VaultShareConfig) []repoConf.VaultPolicyPathConfig {
+	var paths []repoConf.VaultPolicyPathConfig
+	for _, share := range shares {
+		mount := StorePath(share.Repository)
+		if len(share.Secrets) == 0 {
+			paths = append(paths,
+				repoConf.VaultPolicyPathConfig{Path: fmt.Sprintf("%s/data/*", mount), Capabilities: []string{"read"}},
+				repoConf.VaultPolicyPathConfig{Path: fmt.Sprintf("%s/metadata/*", mount), Capabilities: []string{"list"}},
+			)
+			continue
+		}
+		for _, secret := range share.Secrets {
+			paths = append(paths, repoConf.VaultPolicyPathConfig{
+				Path:         fmt.Sprintf("%s/data/%s", mount, secret),
+				Capabilities: []string{"read"},
+			})
+		}
+	}
+
+	return paths
+}
@@ -1,11 +1,14 @@
 package vault
 
 import (
+	"errors"
 	"fmt"
 	"iter"
 	"maps"
 
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
 	"github.com/muhlba91/github-infrastructure/pkg/lib/config"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
 	repoConf "github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
 	vaultConf "github.com/muhlba91/github-infrastructure/pkg/model/config/vault"
@@ -17,7 +20,10 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// ConfigureStores configures Vault secret stores for the given GitHub repositories.
+// ConfigureStores configures Vault secret stores for the given GitHub repositories, and the configured JWT authentication backends.
+// It returns the stores and the inventory of the mount paths and roles, both keyed by repository name,
+// and the errors of all repositories whose stores could not be configured, or whose secrets cannot be shared.
+// No stores are configured if Vault is disabled.
 // ctx: The Pulumi context.
 // repositories: A slice of repository configurations.
 // githubRepositories: A map of GitHub repository resources keyed by repository name.
@@ -29,87 +35,119 @@ func ConfigureStores(
 	githubRepositories map[string]*github.Repository,
 	repositoriesConfig *repositories.Config,
 	vaultConfig *vaultConf.Config,
-) pulumi.Output {
-	return config.HasVaultConnection.ApplyT(func(hasVaultConn bool) map[string]*vault.Mount {
-		if !hasVaultConn {
-			return map[string]*vault.Mount{}
+) (map[string]*vault.Mount, integration.Inventory, error) {
+	if !config.VaultEnabled {
+		return map[string]*vault.Mount{}, integration.Inventory{}, nil
+	}
+
+	backends, bErr := configureAuthBackends(ctx, vaultConfig)
+	if bErr != nil {
+		return nil, nil, bErr
+	}
+
+	repos, additionalMounts := filterRepositories(repositories)
+
+	var errs []error
+	for path := range additionalMounts {
+		_, addErr := store.Create(ctx, path, &store.CreateOptions{
+			Path:        pulumi.String(path),
+			Description: pulumi.String("Secrets for: " + path),
+			PulumiOptions: []pulumi.ResourceOption{
+				pulumi.Provider(config.VaultProvider),
+			},
+		})
+		if addErr != nil {
+			log.Err(addErr).Msgf("[vault][store] error creating vault store for additional mount: %s", path)
+			errs = append(errs, fmt.Errorf("[vault][%s] %w", path, addErr))
 		}
+	}
 
-		repos, additionalMounts := filterRepositories(repositories)
-
-		for path := range additionalMounts {
-			_, addErr := store.Create(ctx, path, &store.CreateOptions{
-				Path:        pulumi.String(path),
-				Description: pulumi.String("Secrets for: " + path),
-				PulumiOptions: []pulumi.ResourceOption{
-					pulumi.Provider(config.VaultProvider),
-				},
-			})
-			if addErr != nil {
-				log.Err(addErr).Msgf("[vault][store] error creating vault store for additional mount: %s", path)
-				return nil
-			}
+	repositoryMounts := make(map[string]*vault.Mount)
+	for _, repository := range repos {
+		mount, stErr := store.Create(ctx, repository.Name, &store.CreateOptions{
+			Path: pulumi.String(StorePath(repository.Name)),
+			Description: pulumi.String(
+				fmt.Sprintf("GitHub repository: %s/%s", *repositoriesConfig.Owner, repository.Name),
+			),
+			PulumiOptions: component.WithRepository(ctx, repository.Name, pulumi.Provider(config.VaultProvider)),
+		})
+		if stErr != nil {
+			log.Err(stErr).Msgf("[vault][store] error creating vault store for repository: %s", repository.Name)
+			errs = append(errs, fmt.Errorf("[vault][%s] %w", repository.Name, stErr))
+			continue
 		}
+		repositoryMounts[repository.Name] = mount
+	}
 
-		repositoryMounts := make(map[string]*vault.Mount)
-		for _, repository := range repos {
-			mount, stErr := store.Create(ctx, repository.Name, &store.CreateOptions{
-				Path: pulumi.String(fmt.Sprintf("github-%s", repository.Name)),
-				Description: pulumi.String(
-					fmt.Sprintf("GitHub repository: %s/%s", *repositoriesConfig.Owner, repository.Name),
-				),
-				PulumiOptions: []pulumi.ResourceOption{
-					pulumi.Provider(config.VaultProvider),
-				},
-			})
-			if stErr != nil {
-				log.Err(stErr).Msgf("[vault][store] error creating vault store for repository: %s", repository.Name)
-				return nil
-			}
+	shares, shErrs := ResolveShares(repositories, func(repository string) bool {
+		_, ok := repositoryMounts[repository]
+		return ok
+	})
+	errs = append(errs, shErrs...)
 
-			_, err := createAuth(
-				ctx,
-				repository,
-				mount,
-				githubRepositories[repository.Name],
-				repositoriesConfig,
-				vaultConfig,
-			)
-			if err != nil {
-				log.Err(err).Msgf("[vault][store] error creating vault authentication")
-				return nil
-			}
+	inventory := make(integration.Inventory)
+	for _, repository := range repos {
+		mount, ok := repositoryMounts[repository.Name]
+		if !ok {
+			continue
+		}
+
+		jwtRole, scopedRoles, err := createAuth(
+			ctx,
+			repository,
+			mount,
+			githubRepositories[repository.Name],
+			repositoriesConfig,
+			vaultConfig,
+			backends,
+			shares[repository.Name],
+		)
+		if err != nil {
+			log.Err(err).Msgf("[vault][store] error creating vault authentication for repository: %s", repository.Name)
+			errs = append(errs, fmt.Errorf("[vault][%s] %w", repository.Name, err))
+			continue
+		}
 
-			repositoryMounts[repository.Name] = mount
+		details := pulumi.Map{
+			"mount": mount.Path,
+			"path":  jwtRole.Backend,
+			"role":  jwtRole.RoleName,
+		}
+		if len(scopedRoles) > 0 {
+			roles := make(pulumi.StringMap)
+			for name, role := range scopedRoles {
+				roles[name] = role.RoleName
+			}
+			details["roles"] = roles
 		}
+		inventory[repository.Name] = details
+	}
 
-		return repositoryMounts
-	})
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	return repositoryMounts, inventory, nil
 }
 
 // filterRepositories filters the given repositories to include only those that we want to manage the lifecycle for.
+// It also returns the paths of the additional mounts to create, including the ones of scoped roles.
 // repositories: A slice of repository configurations.
 func filterRepositories(repositories []*repoConf.Config) ([]*repoConf.Config, iter.Seq[string]) {
 	var repos []*repoConf.Config
 	addMountsTmp := make(map[string]bool)
 	for _, repository := range repositories {
-		repoAccessPermissions := defaults.GetOrDefault(
-			repository.AccessPermissions,
-			repoConf.AccessPermissionsConfig{},
-		)
-		repoVaultAccessPermissions := defaults.GetOrDefault(
-			repoAccessPermissions.Vault,
-			repoConf.VaultAccessPermissionsConfig{},
-		)
-		vEnabled := defaults.GetOrDefault(repoVaultAccessPermissions.Enabled, true)
-		if defaults.GetOrDefault(repository.ManageLifecycle, true) && vEnabled {
+		if vaultManaged(repository) {
 			repos = append(repos, repository)
 
-			if repoVaultAccessPermissions.AdditionalMounts != nil {
-				for _, mount := range repoVaultAccessPermissions.AdditionalMounts {
-					if defaults.GetOrDefault(mount.Create, false) {
-						addMountsTmp[mount.Path] = true
-					}
+			repoVaultAccessPermissions := vaultPermissions(repository)
+			additionalMounts := repoVaultAccessPermissions.AdditionalMounts
+			for _, role := range repoVaultAccessPermissions.Roles {
+				additionalMounts = append(additionalMounts, role.AdditionalMounts...)
+			}
+			for _, mount := range additionalMounts {
+				if defaults.GetOrDefault(mount.Create, false) {
+					addMountsTmp[mount.Path] = true
 				}
 			}
 		}
@@ -117,3 +155,11 @@ func filterRepositories(repositories []*repoConf.Config) ([]*repoConf.Config, it
 
 	return repos, maps.Keys(addMountsTmp)
 }
+
+// vaultManaged returns whether a Vault store is created for the given repository if Vault is enabled for the stack,
+// i.e. the repository's lifecycle is managed, and Vault is not disabled for it.
+// repository: The repository configuration.
+func vaultManaged(repository *repoConf.Config) bool {
+	return defaults.GetOrDefault(repository.ManageLifecycle, true) &&
+		defaults.GetOrDefault(vaultPermissions(repository).Enabled, true)
+}
@@ -0,0 +1,46 @@
+package guardrail
+
+// Config defines the guardrail rules evaluated against every repository configuration.
+type Config struct {
+	// Rules are the guardrail rules.
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule defines a guardrail rule.
+// A rule applies to a repository if all of its 'when' conditions hold,
+// and is violated if any of its 'require' conditions does not hold.
+type Rule struct {
+	// Name is the unique name of the rule.
+	Name string `yaml:"name"`
+	// Description describes the rule, and is reported on violations.
+	Description string `yaml:"description,omitempty"`
+	// Level is the level of the rule: 'warn' reports violations, 'deny' fails the run.
+	Level string `yaml:"level"`
+	// When are the conditions a repository must match for the rule to apply; a rule without conditions applies to all.
+	When []Condition `yaml:"when,omitempty"`
+	// Require are the conditions a repository the rule applies to must fulfill.
+	Require []Condition `yaml:"require"`
+}
+
+// Condition defines a predicate on the values at a path of the evaluated document.
+// Exactly one operator must be set.
+type Condition struct {
+	// Path is the dot-separated path of the values, rooted at 'repository' or 'stack'; '*' matches any key or element.
+	Path string `yaml:"path"`
+	// Default is the value used if the path does not exist.
+	Default any `yaml:"default,omitempty"`
+	// Equals holds if any value equals the given value.
+	Equals any `yaml:"equals,omitempty"`
+	// NotEquals holds if no value equals the given value.
+	NotEquals any `yaml:"notEquals,omitempty"`
+	// Contains holds if any list value contains the given value.
+	Contains any `yaml:"contains,omitempty"`
+	// NotContains holds if no list value contains the given value.
+	NotContains any `yaml:"notContains,omitempty"`
+	// Matches holds if any value matches the given regular expression.
+	Matches string `yaml:"matches,omitempty"`
+	// NotMatches holds if no value matches the given regular expression.
+	NotMatches string `yaml:"notMatches,omitempty"`
+	// Exists holds if the path exists, or does not exist if set to false.
+	Exists *bool `yaml:"exists,omitempty"`
+}
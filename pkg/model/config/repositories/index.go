@@ -6,4 +6,9 @@ type Config struct {
 	Owner *string `yaml:"owner,omitempty"`
 	// Subscription indicates the GitHub subscription status.
 	Subscription *string `yaml:"subscription,omitempty"`
+	// StrictReferences indicates whether repositories referencing unconfigured cloud projects or accounts
+	// fail the deployment instead of being skipped.
+	StrictReferences *bool `yaml:"strictReferences,omitempty"`
+	// Defaults contains the baseline repository configuration every repository configuration is layered on top of.
+	Defaults map[string]any `yaml:"defaults,omitempty"`
 }
@@ -13,4 +13,6 @@ type AccessPermissionsConfig struct {
 	Aws *AwsAccessConfig `yaml:"aws,omitempty"`
 	// Scaleway defines the Scaleway access config.
 	Scaleway *ScalewayAccessConfig `yaml:"scaleway,omitempty"`
+	// Sink defines where the credentials are stored: 'vault', 'secrets', or 'variables'.
+	Sink *string `yaml:"sink,omitempty"`
 }
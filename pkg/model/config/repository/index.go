@@ -22,6 +22,8 @@ type Config struct {
 	EnableDiscussions *bool `yaml:"enableDiscussions,omitempty"`
 	// CreateProject indicates whether to create a project for the repository.
 	CreateProject *bool `yaml:"createProject,omitempty"`
+	// Project defines the project config; only used if CreateProject is enabled.
+	Project *ProjectConfig `yaml:"project,omitempty"`
 	// EnablePages indicates whether to enable GitHub Pages.
 	EnablePages *bool `yaml:"enablePages,omitempty"`
 	// Rulesets defines the repository rulesets config.
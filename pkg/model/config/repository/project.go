@@ -0,0 +1,23 @@
+package repository
+
+// ProjectConfig defines repository project config.
+type ProjectConfig struct {
+	// Name is the name of the project.
+	Name *string `yaml:"name,omitempty"`
+	// Description is the description of the project.
+	Description *string `yaml:"description,omitempty"`
+	// Columns defines the options of the project's status field, i.e. the columns of its board.
+	Columns []string `yaml:"columns,omitempty"`
+	// Fields defines the additional fields of the project.
+	Fields []ProjectFieldConfig `yaml:"fields,omitempty"`
+}
+
+// ProjectFieldConfig defines a custom field of a repository project.
+type ProjectFieldConfig struct {
+	// Name is the name of the field.
+	Name string `yaml:"name"`
+	// Type is the type of the field.
+	Type string `yaml:"type"`
+	// Options are the options of a single select field.
+	Options []string `yaml:"options,omitempty"`
+}
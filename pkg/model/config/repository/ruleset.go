@@ -2,10 +2,18 @@ package repository
 
 // RulesetConfig defines repository branch protections config.
 type RulesetConfig struct {
+	// Name is the name of the ruleset; only used for custom rulesets.
+	Name string `yaml:"name,omitempty"`
 	// Enabled indicates whether the ruleset is enabled.
 	Enabled bool `yaml:"enabled"`
-	// Patterns defines the branch patterns to which the ruleset applies.
+	// Target defines the target of the ruleset (branch, tag or push).
+	Target *string `yaml:"target,omitempty"`
+	// Enforcement defines the enforcement mode of the ruleset (active, evaluate or disabled).
+	Enforcement *string `yaml:"enforcement,omitempty"`
+	// Patterns defines the branch patterns to which the ruleset applies; required for custom branch and tag rulesets.
 	Patterns []string `yaml:"patterns,omitempty"`
+	// ExcludePatterns defines the branch patterns which are excluded from the ruleset.
+	ExcludePatterns []string `yaml:"excludePatterns,omitempty"`
 	// RestrictCreation indicates whether to restrict creation.
 	RestrictCreation *bool `yaml:"restrictCreation,omitempty"`
 	// AllowForcePush indicates whether to allow force push.
@@ -34,4 +42,10 @@ type RulesetConfig struct {
 	EnableGitstreamIntegration *bool `yaml:"enableGitstreamIntegration,omitempty"`
 	// EnableWipIntegration indicates whether to enable the WIP integration.
 	EnableWipIntegration *bool `yaml:"enableWipIntegration,omitempty"`
+	// RestrictedFilePaths defines the file paths which may not be pushed; only used for push rulesets.
+	RestrictedFilePaths []string `yaml:"restrictedFilePaths,omitempty"`
+	// MaxFileSize defines the maximum size of pushed files in MB; only used for push rulesets.
+	MaxFileSize *int `yaml:"maxFileSize,omitempty"`
+	// RestrictedFileExtensions defines the file extensions which may not be pushed; only used for push rulesets.
+	RestrictedFileExtensions []string `yaml:"restrictedFileExtensions,omitempty"`
 }
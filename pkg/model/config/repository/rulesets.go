@@ -6,4 +6,6 @@ type RulesetsConfig struct {
 	Branch *RulesetConfig `yaml:"branch,omitempty"`
 	// Tag defines the repository tag protections config.
 	Tag *RulesetConfig `yaml:"tag,omitempty"`
+	// Custom defines additional named rulesets.
+	Custom []*RulesetConfig `yaml:"custom,omitempty"`
 }
@@ -1,5 +1,7 @@
 package repository
 
+import "github.com/muhlba91/github-infrastructure/pkg/model/config/vault"
+
 // VaultAccessPermissionsConfig defines vault access permissions config.
 type VaultAccessPermissionsConfig struct {
 	// Enabled indicates whether vault access is enabled.
@@ -8,6 +10,18 @@ type VaultAccessPermissionsConfig struct {
 	Address *string `yaml:"address,omitempty"`
 	// AdditionalMounts defines additional vault mount access permissions config.
 	AdditionalMounts []VaultAdditionalMountAccessPermissionsConfig `yaml:"additionalMounts,omitempty"`
+	// Roles defines additional Vault JWT roles scoped to refs, environments, or workflows.
+	Roles []VaultRoleConfig `yaml:"roles,omitempty"`
+	// PolicyTemplates are the names of the policy templates in 'assets/vault/policies/' appended to the policies.
+	PolicyTemplates []string `yaml:"policyTemplates,omitempty"`
+	// Paths defines additional policy paths.
+	Paths []VaultPolicyPathConfig `yaml:"paths,omitempty"`
+	// ReadFrom defines the repositories whose secrets the repository reads.
+	ReadFrom []VaultShareConfig `yaml:"readFrom,omitempty"`
+	// ShareWith defines the repositories the repository's secrets are shared with.
+	ShareWith []VaultShareConfig `yaml:"shareWith,omitempty"`
+	// Auth overrides the stack's JWT authentication settings of the repository's roles.
+	Auth *vault.GitHubAuthConfig `yaml:"auth,omitempty"`
 }
 
 // VaultAdditionalMountAccessPermissionsConfig defines vault additional mount access permissions config.
@@ -19,3 +33,35 @@ type VaultAdditionalMountAccessPermissionsConfig struct {
 	// Permissions defines the permissions for the mount.
 	Permissions []string `yaml:"permissions"`
 }
+
+// VaultPolicyPathConfig defines an additional path of a vault policy.
+type VaultPolicyPathConfig struct {
+	// Path is the path, which may contain globs.
+	Path string `yaml:"path"`
+	// Capabilities defines the capabilities granted on the path.
+	Capabilities []string `yaml:"capabilities"`
+	// RequiredParameters defines the parameters which must be given in requests to the path.
+	RequiredParameters []string `yaml:"requiredParameters,omitempty"`
+}
+
+// VaultShareConfig defines secrets shared between repositories.
+type VaultShareConfig struct {
+	// Repository is the name of the repository reading or sharing the secrets.
+	Repository string `yaml:"repository"`
+	// Secrets are the keys of the shared secrets; all secrets are shared if empty.
+	Secrets []string `yaml:"secrets,omitempty"`
+}
+
+// VaultRoleConfig defines an additional Vault JWT role scoped to GitHub Actions claims.
+type VaultRoleConfig struct {
+	// Name is the name of the role, which is appended to the repository's role name.
+	Name string `yaml:"name"`
+	// Refs are the Git refs the role is bound to, e.g. 'refs/heads/main'.
+	Refs []string `yaml:"refs,omitempty"`
+	// Environments are the GitHub environments the role is bound to.
+	Environments []string `yaml:"environments,omitempty"`
+	// Workflows are the workflow refs the role is bound to, e.g. 'owner/repository/.github/workflows/deploy.yml@refs/heads/main'.
+	Workflows []string `yaml:"workflows,omitempty"`
+	// AdditionalMounts defines the additional vault mount access permissions of the role.
+	AdditionalMounts []VaultAdditionalMountAccessPermissionsConfig `yaml:"additionalMounts,omitempty"`
+}
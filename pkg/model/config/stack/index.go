@@ -0,0 +1,23 @@
+package stack
+
+import (
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/aws"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/google"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/repositories"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/scaleway"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/vault"
+)
+
+// Config defines the project configuration of a Pulumi stack.
+type Config struct {
+	// Repositories contains the repositories configuration.
+	Repositories *repositories.Config `yaml:"repositories,omitempty"`
+	// Aws contains the AWS configuration.
+	Aws *aws.Config `yaml:"aws,omitempty"`
+	// Google contains the Google Cloud configuration.
+	Google *google.Config `yaml:"google,omitempty"`
+	// Scaleway contains the Scaleway configuration.
+	Scaleway *scaleway.Config `yaml:"scaleway,omitempty"`
+	// Vault contains the Vault configuration.
+	Vault *vault.Config `yaml:"vault,omitempty"`
+}
@@ -6,4 +6,90 @@ type Config struct {
 	Enabled *bool `yaml:"enabled,omitempty"`
 	// Address is the address of the Vault server.
 	Address *string `yaml:"address,omitempty"`
+	// Auth defines how to authenticate to Vault; defaults to the token of the core infrastructure stack.
+	Auth *AuthConfig `yaml:"auth,omitempty"`
+	// GitHubAuth defines the JWT authentication of the repositories' GitHub Actions workflows to Vault.
+	GitHubAuth *GitHubAuthConfig `yaml:"githubAuth,omitempty"`
+	// AuthBackends defines the JWT authentication backends to create; existing backends are used if not set.
+	AuthBackends []AuthBackendConfig `yaml:"authBackends,omitempty"`
+}
+
+// AuthBackendConfig defines a JWT authentication backend of an OIDC issuer.
+type AuthBackendConfig struct {
+	// Path is the path of the backend.
+	Path *string `yaml:"path,omitempty"`
+	// Description is the description of the backend.
+	Description *string `yaml:"description,omitempty"`
+	// OIDCDiscoveryURL is the OIDC discovery URL of the issuer; defaults to the one of GitHub Actions.
+	OIDCDiscoveryURL *string `yaml:"oidcDiscoveryUrl,omitempty"`
+	// BoundIssuer is the issuer tokens must be issued by; defaults to the OIDC discovery URL.
+	BoundIssuer *string `yaml:"boundIssuer,omitempty"`
+	// DefaultRole is the role used if none is given on login.
+	DefaultRole *string `yaml:"defaultRole,omitempty"`
+}
+
+// GitHubAuthConfig defines the JWT authentication backend roles of the repositories.
+type GitHubAuthConfig struct {
+	// Path is the path of the JWT authentication backend; defaults to 'github'.
+	Path *string `yaml:"path,omitempty"`
+	// Audiences are the audiences bound to the roles; defaults to 'https://github.com/<owner>'.
+	Audiences []string `yaml:"audiences,omitempty"`
+	// TokenTTL is the time-to-live of issued tokens in seconds; defaults to one hour.
+	TokenTTL *int `yaml:"tokenTtl,omitempty"`
+	// TokenMaxTTL is the maximum time-to-live of issued tokens in seconds; defaults to the system maximum.
+	TokenMaxTTL *int `yaml:"tokenMaxTtl,omitempty"`
+	// TokenType is the type of issued tokens: 'default', 'service', 'batch', 'default-service', or 'default-batch'.
+	TokenType *string `yaml:"tokenType,omitempty"`
+	// BoundCIDRs are the CIDR blocks the roles can be assumed from, and issued tokens can be used from.
+	BoundCIDRs []string `yaml:"boundCidrs,omitempty"`
+}
+
+// AuthConfig defines the authentication to Vault.
+type AuthConfig struct {
+	// Method is the authentication method: 'stackReference' (default), 'token', 'appRole', or 'jwt'.
+	Method *string `yaml:"method,omitempty"`
+	// StackReference defines the stack output containing the token; used by the 'stackReference' method.
+	StackReference *StackReferenceAuthConfig `yaml:"stackReference,omitempty"`
+	// Token defines the source of the token; used by the 'token' method.
+	Token *SecretSourceConfig `yaml:"token,omitempty"`
+	// AppRole defines the AppRole login; used by the 'appRole' method.
+	AppRole *AppRoleAuthConfig `yaml:"appRole,omitempty"`
+	// JWT defines the JWT login; used by the 'jwt' method.
+	JWT *JWTAuthConfig `yaml:"jwt,omitempty"`
+}
+
+// StackReferenceAuthConfig defines the stack output containing the Vault token.
+type StackReferenceAuthConfig struct {
+	// Name is the fully qualified name of the stack; defaults to the core infrastructure stack of the same environment.
+	Name *string `yaml:"name,omitempty"`
+	// OutputPath is the dot-separated path of the token in the stack's outputs; defaults to 'vault.keys.rootToken'.
+	OutputPath *string `yaml:"outputPath,omitempty"`
+}
+
+// SecretSourceConfig defines where a secret is read from.
+type SecretSourceConfig struct {
+	// Env is the environment variable containing the secret.
+	Env *string `yaml:"env,omitempty"`
+	// File is the file containing the secret; takes precedence over the environment variable.
+	File *string `yaml:"file,omitempty"`
+}
+
+// AppRoleAuthConfig defines the login via the AppRole authentication method.
+type AppRoleAuthConfig struct {
+	// Mount is the path of the AppRole authentication method; defaults to 'approle'.
+	Mount *string `yaml:"mount,omitempty"`
+	// RoleID is the ID of the role.
+	RoleID *string `yaml:"roleId,omitempty"`
+	// SecretID defines the source of the secret ID; defaults to the 'VAULT_SECRET_ID' environment variable.
+	SecretID *SecretSourceConfig `yaml:"secretId,omitempty"`
+}
+
+// JWTAuthConfig defines the login via the JWT authentication method.
+type JWTAuthConfig struct {
+	// Mount is the path of the JWT authentication method; defaults to 'jwt'.
+	Mount *string `yaml:"mount,omitempty"`
+	// Role is the name of the role.
+	Role *string `yaml:"role,omitempty"`
+	// Token defines the source of the JWT; defaults to the 'VAULT_JWT' environment variable.
+	Token *SecretSourceConfig `yaml:"token,omitempty"`
 }
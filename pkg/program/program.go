@@ -0,0 +1,149 @@
+package program
+
+import (
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/aws"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/component"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/config"
+	ghRepos "github.com/muhlba91/github-infrastructure/pkg/lib/github/repositories"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/gitlab"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/google"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/integration"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/scaleway"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/sink"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/tailscale"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/vault"
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	"github.com/pulumi/pulumi-command/sdk/go/command/local"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Run is the body of the Pulumi program.
+// It creates all repositories, their Vault stores, and the resources of all integrations, and exports the outputs.
+// ctx: The Pulumi context.
+func Run(ctx *pulumi.Context) error {
+	repositoriesConfig, awsConfig, gcpConfig, scalewayConfig, vaultConfig, repos, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	// repository components
+	defer component.Reset(ctx)
+	for _, repo := range repos {
+		if _, cErr := component.NewRepository(ctx, repo.Name); cErr != nil {
+			return cErr
+		}
+	}
+
+	// repositories
+	githubRepositories, githubProjects, ghErr := ghRepos.Create(ctx, repos, repositoriesConfig)
+	if ghErr != nil {
+		return ghErr
+	}
+
+	// vault stores
+	vaultStores, vaultInventory, vErr := vault.ConfigureStores(ctx, repos, githubRepositories, repositoriesConfig, vaultConfig)
+	if vErr != nil {
+		return vErr
+	}
+
+	// credential sinks
+	sinks := sink.Create(repos, vaultStores, githubRepositories)
+
+	// integrations
+	integrations, iErr := integration.NewRegistry(
+		gitlab.NewIntegration(),
+		tailscale.NewIntegration(),
+		google.NewIntegration(gcpConfig, repositoriesConfig),
+		aws.NewIntegration(awsConfig, repositoriesConfig),
+		scaleway.NewIntegration(scalewayConfig, repositoriesConfig),
+	)
+	if iErr != nil {
+		return iErr
+	}
+	var errs []error
+	inventories := map[string]integration.Inventory{
+		"vault": vaultInventory,
+	}
+	for _, i := range integrations.Integrations() {
+		configured, inventory, cErr := i.Configure(ctx, integration.Filter(i, repos), sinks)
+		if cErr != nil {
+			errs = append(errs, cErr)
+			continue
+		}
+		ctx.Export(i.Name(), pulumi.ToMap(i.Outputs(configured)))
+		inventories[i.Name()] = inventory
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	// outputs
+	ctx.Export("vault", pulumi.ToMap(map[string]any{
+		"projects": slices.Sorted(maps.Keys(vaultStores)),
+	}))
+	exportRepositories(ctx, repos, integrations, githubProjects)
+	exportInventory(ctx, repos, inventories)
+
+	return nil
+}
+
+// exportRepositories exports a summary of the configured repositories and their access permissions.
+// ctx: The Pulumi context used for exporting outputs.
+// repos: A slice of repository configurations to be summarized.
+// integrations: The registry of integrations contributing per-repository flags.
+// projects: A map of the commands managing GitHub projects keyed by repository name.
+func exportRepositories(
+	ctx *pulumi.Context,
+	repos []*repository.Config,
+	integrations *integration.Registry,
+	projects map[string]*local.Command,
+) {
+	repositories := make(map[string]map[string]any)
+	for _, repo := range repos {
+		repositories[repo.Name] = make(map[string]any)
+		for _, i := range integrations.Integrations() {
+			for flag, value := range i.Flags(repo) {
+				repositories[repo.Name][flag] = value
+			}
+		}
+		repositories[repo.Name]["vault"] = defaults.GetOrDefault(repo.ManageLifecycle, true) &&
+			repo.AccessPermissions != nil &&
+			repo.AccessPermissions.Vault != nil &&
+			defaults.GetOrDefault(repo.AccessPermissions.Vault.Enabled, true)
+		if project, ok := projects[repo.Name]; ok {
+			// the command prints the ID of the project
+			repositories[repo.Name]["project"] = project.Stdout
+		}
+	}
+
+	ctx.Export("repositories", pulumi.ToMapMap(repositories))
+}
+
+// exportInventory exports the non-secret details of the credentials configured for each repository,
+// keyed by repository name and the name of the integration (or "vault").
+// ctx: The Pulumi context used for exporting outputs.
+// repos: A slice of repository configurations.
+// inventories: The inventories keyed by the name of the integration.
+func exportInventory(
+	ctx *pulumi.Context,
+	repos []*repository.Config,
+	inventories map[string]integration.Inventory,
+) {
+	inventory := make(pulumi.Map)
+	for _, repo := range repos {
+		details := make(pulumi.Map)
+		for name, integrationInventory := range inventories {
+			if repoDetails, ok := integrationInventory[repo.Name]; ok {
+				details[name] = repoDetails
+			}
+		}
+		inventory[repo.Name] = details
+	}
+
+	ctx.Export("inventory", inventory)
+}
@@ -0,0 +1,318 @@
+package program_test
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"gopkg.in/yaml.v3"
+
+	"github.com/muhlba91/github-infrastructure/pkg/lib/guide"
+	"github.com/muhlba91/github-infrastructure/pkg/lib/sink"
+	stackConf "github.com/muhlba91/github-infrastructure/pkg/model/config/stack"
+	"github.com/muhlba91/github-infrastructure/pkg/program"
+	"github.com/muhlba91/github-infrastructure/pkg/util"
+	"github.com/muhlba91/github-infrastructure/test/mocks"
+)
+
+const (
+	// project is the name of the Pulumi project.
+	project = "muehlbachler-github-infrastructure"
+	// stack is the name of the stack the fixtures are run as.
+	stack = "test"
+	// fixturesDir is the directory containing one directory per test case.
+	fixturesDir = "../../test/fixtures"
+	// assetsDir is the directory containing the assets shared by all test cases.
+	assetsDir = "../../assets"
+	// fixtureFile is the file containing the configuration of a test case.
+	fixtureFile = "fixture.yaml"
+	// goldenFile is the file containing the expected resources of a test case.
+	goldenFile = "resources.golden.json"
+	// actionsSecretType is the type token of GitHub Actions secrets.
+	actionsSecretType = "github:index/actionsSecret:ActionsSecret"
+	// actionsVariableType is the type token of GitHub Actions variables.
+	actionsVariableType = "github:index/actionsVariable:ActionsVariable"
+	// githubSecret is the kind of credentials stored as GitHub Actions secrets.
+	githubSecret = "secret"
+	// githubVariable is the kind of credentials stored as GitHub Actions variables.
+	githubVariable = "variable"
+)
+
+//nolint:gochecknoglobals // test flags are globals
+var update = flag.Bool("update", false, "update the golden files with the registered resources")
+
+// fixture is the configuration of a test case.
+type fixture struct {
+	// Config is the project configuration of the stack, keyed without the project prefix.
+	Config map[string]any `yaml:"config"`
+	// StackReferences are the outputs of referenced stacks keyed by their project name.
+	StackReferences map[string]map[string]any `yaml:"stackReferences"`
+	// Env are the environment variables set while running the program.
+	Env map[string]string `yaml:"env"`
+	// Error are parts of the error the program is expected to fail with, one per line; no golden file is compared then.
+	Error string `yaml:"error"`
+}
+
+// TestRun runs the program against every fixture and compares the registered resources with the golden files,
+// or checks the error of fixtures expected to fail.
+// Run the tests with -update to regenerate the golden files, and review their changes.
+func TestRun(t *testing.T) {
+	for name, dir := range fixtureDirs(t) {
+		t.Run(name, func(t *testing.T) {
+			runFixture(t, dir)
+		})
+	}
+}
+
+// TestGuideSecrets checks that the usage guides list exactly the credentials the integrations store.
+// The GitHub Actions secrets and variables registered for every repository not using Vault are compared
+// with the keys and fields of the guide's secrets, and with whether they are stored as secrets or variables.
+func TestGuideSecrets(t *testing.T) {
+	for name, dir := range fixtureDirs(t) {
+		t.Run(name, func(t *testing.T) {
+			f, monitor, rErr := runProgram(t, dir)
+			if f.Error != "" {
+				t.Skip("fixture is expected to fail")
+			}
+			if rErr != nil {
+				t.Fatalf("error running program: %v", rErr)
+			}
+
+			stackConfig := parseStackConfig(t, f)
+			var repositoriesDefaults map[string]any
+			if stackConfig.Repositories != nil {
+				repositoriesDefaults = stackConfig.Repositories.Defaults
+			}
+			repos, pErr := util.ParseRepositoriesFromFiles(
+				"./assets/repositories",
+				"./assets/templates/profiles",
+				repositoriesDefaults,
+			)
+			if pErr != nil {
+				t.Fatalf("error parsing repositories: %v", pErr)
+			}
+
+			stored := storedCredentials(monitor)
+			for _, repo := range repos {
+				kind := guide.Sink(repo)
+				if kind == sink.Vault {
+					continue
+				}
+
+				expected := make(map[string]string)
+				for _, secret := range guide.Secrets(repo, stackConfig) {
+					for _, field := range secret.Fields {
+						expected[sink.Name(secret.Key, field.Name)] = githubSecret
+						if kind == sink.Variables && !field.Sensitive {
+							expected[sink.Name(secret.Key, field.Name)] = githubVariable
+						}
+					}
+				}
+				if !maps.Equal(expected, stored[repo.Name]) {
+					t.Errorf("guide of repository %s lists %v, but the integrations store %v",
+						repo.Name, expected, stored[repo.Name])
+				}
+			}
+		})
+	}
+}
+
+// fixtureDirs returns the absolute paths of all fixture directories keyed by the name of the test case.
+// t: The test.
+func fixtureDirs(t *testing.T) map[string]string {
+	entries, err := os.ReadDir(fixturesDir)
+	if err != nil {
+		t.Fatalf("error reading fixtures: %v", err)
+	}
+
+	dirs := make(map[string]string)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir, aErr := filepath.Abs(filepath.Join(fixturesDir, entry.Name()))
+		if aErr != nil {
+			t.Fatalf("error resolving fixture %s: %v", entry.Name(), aErr)
+		}
+		dirs[entry.Name()] = dir
+	}
+	return dirs
+}
+
+// runFixture runs the program against the fixture in the given directory.
+// t: The test.
+// dir: The absolute path of the fixture directory.
+func runFixture(t *testing.T, dir string) {
+	f, monitor, rErr := runProgram(t, dir)
+	if f.Error != "" {
+		for _, expected := range strings.Split(strings.TrimSpace(f.Error), "\n") {
+			if rErr == nil || !strings.Contains(rErr.Error(), expected) {
+				t.Fatalf("expected program to fail with '%s', got: %v", expected, rErr)
+			}
+		}
+		return
+	}
+	if rErr != nil {
+		t.Fatalf("error running program: %v", rErr)
+	}
+
+	actual, jErr := json.MarshalIndent(monitor.Resources(), "", "  ")
+	if jErr != nil {
+		t.Fatalf("error encoding resources: %v", jErr)
+	}
+	actual = append(actual, '\n')
+
+	golden := filepath.Join(dir, goldenFile)
+	if *update {
+		if wErr := os.WriteFile(golden, actual, 0o644); wErr != nil { //nolint:gosec // golden files are not sensitive
+			t.Fatalf("error writing golden file: %v", wErr)
+		}
+		return
+	}
+
+	expected, gErr := os.ReadFile(golden)
+	if errors.Is(gErr, fs.ErrNotExist) {
+		t.Fatalf("golden file %s does not exist; run the tests with -update to create it", golden)
+	}
+	if gErr != nil {
+		t.Fatalf("error reading golden file: %v", gErr)
+	}
+	if line, ok := firstDifference(string(expected), string(actual)); !ok {
+		t.Errorf("registered resources differ from %s at line %d; run the tests with -update and review the diff",
+			golden, line)
+	}
+}
+
+// runProgram runs the program against the fixture in the given directory, from a working directory laid out
+// like the repository's assets.
+// It returns the fixture, the resource monitor recording the registered resources, and the error of the run.
+// t: The test.
+// dir: The absolute path of the fixture directory.
+func runProgram(t *testing.T, dir string) (*fixture, *mocks.Mocks, error) {
+	b, err := os.ReadFile(filepath.Join(dir, fixtureFile))
+	if err != nil {
+		t.Fatalf("error reading fixture: %v", err)
+	}
+	var f fixture
+	if yErr := yaml.Unmarshal(b, &f); yErr != nil {
+		t.Fatalf("error parsing fixture: %v", yErr)
+	}
+
+	config := make(map[string]string)
+	for key, value := range f.Config {
+		v, jErr := json.Marshal(value)
+		if jErr != nil {
+			t.Fatalf("error encoding configuration %s: %v", key, jErr)
+		}
+		config[fmt.Sprintf("%s:%s", project, key)] = string(v)
+	}
+	for key, value := range f.Env {
+		t.Setenv(key, value)
+	}
+
+	t.Chdir(workDir(t, dir))
+
+	monitor := mocks.New(f.StackReferences)
+	rErr := pulumi.RunErr(
+		program.Run,
+		pulumi.WithMocks(project, stack, monitor),
+		func(info *pulumi.RunInfo) {
+			info.Config = config
+		},
+	)
+	return &f, monitor, rErr
+}
+
+// parseStackConfig decodes the project configuration of the given fixture.
+// t: The test.
+// f: The fixture.
+func parseStackConfig(t *testing.T, f *fixture) *stackConf.Config {
+	b, err := yaml.Marshal(f.Config)
+	if err != nil {
+		t.Fatalf("error encoding configuration: %v", err)
+	}
+	var stackConfig stackConf.Config
+	if yErr := yaml.Unmarshal(b, &stackConfig); yErr != nil {
+		t.Fatalf("error decoding configuration: %v", yErr)
+	}
+	return &stackConfig
+}
+
+// storedCredentials returns the names of the registered GitHub Actions secrets and variables
+// with their kind, keyed by repository name.
+// monitor: The resource monitor of the program run.
+func storedCredentials(monitor *mocks.Mocks) map[string]map[string]string {
+	stored := make(map[string]map[string]string)
+	for _, resource := range monitor.Resources() {
+		var kind, nameInput string
+		switch resource.Type {
+		case actionsSecretType:
+			kind, nameInput = githubSecret, "secretName"
+		case actionsVariableType:
+			kind, nameInput = githubVariable, "variableName"
+		default:
+			continue
+		}
+
+		repository, _ := resource.Inputs["repository"].(string)
+		name, _ := resource.Inputs[nameInput].(string)
+		if stored[repository] == nil {
+			stored[repository] = make(map[string]string)
+		}
+		stored[repository][name] = kind
+	}
+	return stored
+}
+
+// workDir creates a working directory laid out like the repository's assets.
+// The repositories are taken from the fixture, while the profiles, templates, scripts, and guardrail rules are the repository's own.
+// t: The test.
+// dir: The absolute path of the fixture directory.
+func workDir(t *testing.T, dir string) string {
+	wd := t.TempDir()
+	copies := map[string]string{
+		filepath.Join(dir, "repositories"):    filepath.Join(wd, "assets", "repositories"),
+		filepath.Join(assetsDir, "templates"): filepath.Join(wd, "assets", "templates"),
+		filepath.Join(assetsDir, "vault"):     filepath.Join(wd, "assets", "vault"),
+		filepath.Join(assetsDir, "github"):    filepath.Join(wd, "assets", "github"),
+	}
+	for src, dst := range copies {
+		if err := os.CopyFS(dst, os.DirFS(src)); err != nil {
+			t.Fatalf("error copying %s: %v", src, err)
+		}
+	}
+
+	guardrails, err := os.ReadFile(filepath.Join(assetsDir, "guardrails.yml"))
+	if err != nil {
+		t.Fatalf("error reading guardrail rules: %v", err)
+	}
+	if wErr := os.WriteFile(filepath.Join(wd, "assets", "guardrails.yml"), guardrails, 0o600); wErr != nil {
+		t.Fatalf("error writing guardrail rules: %v", wErr)
+	}
+
+	return wd
+}
+
+// firstDifference compares the given texts line by line.
+// It returns the first line number at which they differ, and whether they are equal.
+// expected: The expected text.
+// actual: The actual text.
+func firstDifference(expected string, actual string) (int, bool) {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+	for i := range max(len(expectedLines), len(actualLines)) {
+		if i >= len(expectedLines) || i >= len(actualLines) || expectedLines[i] != actualLines[i] {
+			return i + 1, false
+		}
+	}
+
+	return 0, true
+}
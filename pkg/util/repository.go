@@ -1,23 +1,50 @@
 package util
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 
 	"github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
 	"github.com/rs/zerolog/log"
 	"gopkg.in/yaml.v3"
 )
 
+// defaultsSource is the name used for the repository defaults of the stack configuration in error messages.
+const defaultsSource = "repositories.defaults"
+
 // ParseRepositoriesFromFiles reads the repository configuration files from the specified directory.
-func ParseRepositoriesFromFiles(dir string) ([]*repository.Config, error) {
+// Profiles referenced by a file's 'extends' field are read from the profiles directory and merged into it.
+// Every configuration is layered on top of the given defaults before profiles and the file itself are applied.
+// Every file is validated strictly; all errors of all files are aggregated and returned together.
+// dir: The directory containing the repository configuration files.
+// profilesDir: The directory containing the profiles.
+// defaultsConfig: The baseline repository configuration; may be nil.
+func ParseRepositoriesFromFiles(
+	dir string,
+	profilesDir string,
+	defaultsConfig map[string]any,
+) ([]*repository.Config, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		log.Err(err).Msgf("[repository] error reading repository configuration directory: %s", dir)
 		return nil, err
 	}
 
+	defaultsNode, dErrs := parseDefaults(defaultsConfig)
+	if len(dErrs) > 0 {
+		for _, dErr := range dErrs {
+			log.Error().Msgf("[repository] %s", dErr)
+		}
+		return nil, fmt.Errorf("invalid repository defaults:\n%w", errors.Join(dErrs...))
+	}
+
+	profiles := newProfileLoader(profilesDir, defaultsNode)
+
 	var repos []*repository.Config
+	var errs []error
 	for _, e := range entries {
 		full := filepath.Join(dir, e.Name())
 		b, rErr := os.ReadFile(full)
@@ -26,13 +53,103 @@ func ParseRepositoriesFromFiles(dir string) ([]*repository.Config, error) {
 			return nil, rErr
 		}
 
-		var r repository.Config
-		if yErr := yaml.Unmarshal(b, &r); yErr != nil {
-			log.Err(yErr).Msgf("[repository] error parsing repository configuration file: %s", full)
-			return nil, yErr
+		r, pErrs := parseRepository(full, b, profiles)
+		if len(pErrs) > 0 {
+			for _, pErr := range pErrs {
+				log.Error().Msgf("[repository] %s", pErr)
+			}
+			errs = append(errs, pErrs...)
+			continue
 		}
-		repos = append(repos, &r)
+		repos = append(repos, r)
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid repository configurations:\n%w", errors.Join(errs...))
 	}
 
 	return repos, nil
 }
+
+// parseDefaults converts and validates the baseline repository configuration from the stack configuration.
+// The configuration is re-marshalled from the decoded stack configuration, hence the positions of its nodes
+// do not refer to the stack configuration file and are removed.
+// defaultsConfig: The baseline repository configuration; may be nil.
+func parseDefaults(defaultsConfig map[string]any) (*yaml.Node, []error) {
+	if len(defaultsConfig) == 0 {
+		return nil, nil
+	}
+
+	b, mErr := yaml.Marshal(defaultsConfig)
+	if mErr != nil {
+		return nil, []error{fmt.Errorf("%s: %w", defaultsSource, mErr)}
+	}
+
+	var document yaml.Node
+	if yErr := yaml.Unmarshal(b, &document); yErr != nil {
+		return nil, []error{fmt.Errorf("%s: %w", defaultsSource, yErr)}
+	}
+	root := document.Content[0]
+	clearPositions(root)
+
+	errs := validateFields(defaultsSource, root, reflect.TypeFor[repository.Config](), "")
+	errs = append(errs, validateEnums(defaultsSource, root)...)
+	if name := lookup(root, "name"); name != nil {
+		errs = append(errs, positionError(defaultsSource, name, "field 'name' cannot be set as a default"))
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return root, nil
+}
+
+// clearPositions removes the line and column of the given node and all of its descendants.
+// node: The node to clear.
+func clearPositions(node *yaml.Node) {
+	node.Line, node.Column = 0, 0
+	for _, child := range node.Content {
+		clearPositions(child)
+	}
+}
+
+// parseRepository parses, resolves the profiles of, and validates a single repository configuration.
+// file: The path of the file the configuration was read from.
+// b: The content of the file.
+// profiles: The loader for the profiles referenced by the configuration.
+func parseRepository(file string, b []byte, profiles *profileLoader) (*repository.Config, []error) {
+	var document yaml.Node
+	if yErr := yaml.Unmarshal(b, &document); yErr != nil {
+		return nil, []error{fmt.Errorf("%s: %w", file, yErr)}
+	}
+	if document.Kind == 0 {
+		return nil, []error{fmt.Errorf("%s: empty repository configuration", file)}
+	}
+	if document.Content[0].Kind != yaml.MappingNode {
+		return nil, []error{positionError(file, document.Content[0], "repository configuration must be a mapping")}
+	}
+
+	merged, mErrs := profiles.resolve(file, document.Content[0], nil)
+	if len(mErrs) > 0 {
+		return nil, mErrs
+	}
+
+	if vErrs := validateRepositoryNode(file, merged, reflect.TypeFor[repository.Config]()); len(vErrs) > 0 {
+		return nil, vErrs
+	}
+
+	var r repository.Config
+	if dErr := merged.Decode(&r); dErr != nil {
+		var typeErr *yaml.TypeError
+		if errors.As(dErr, &typeErr) {
+			var errs []error
+			for _, msg := range typeErr.Errors {
+				errs = append(errs, fmt.Errorf("%s: %s", file, msg))
+			}
+			return nil, errs
+		}
+		return nil, []error{fmt.Errorf("%s: %w", file, dErr)}
+	}
+
+	return &r, nil
+}
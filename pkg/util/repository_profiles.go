@@ -0,0 +1,227 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/repository"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// extendsField is the field of a repository configuration referencing the profiles it extends.
+	extendsField = "extends"
+	// appendTag is the YAML tag marking a sequence to be appended to the inherited sequence instead of replacing it.
+	appendTag = "!append"
+	// sequenceTag is the default YAML tag of sequences.
+	sequenceTag = "!!seq"
+)
+
+// profileLoader loads and caches repository profiles from a directory.
+type profileLoader struct {
+	// dir is the directory containing the profiles.
+	dir string
+	// defaults is the baseline configuration all repository configurations are layered on top of; may be nil.
+	defaults *yaml.Node
+	// profiles caches the resolved profiles by name.
+	profiles map[string]*yaml.Node
+}
+
+// newProfileLoader creates a profile loader for the given directory.
+// dir: The directory containing the profiles.
+// defaults: The baseline configuration all repository configurations are layered on top of; may be nil.
+func newProfileLoader(dir string, defaults *yaml.Node) *profileLoader {
+	return &profileLoader{
+		dir:      dir,
+		defaults: defaults,
+		profiles: make(map[string]*yaml.Node),
+	}
+}
+
+// resolve merges the profiles referenced by the given document's 'extends' field into the document.
+// Repository configurations are layered on top of the defaults, profiles are merged in the order they are listed,
+// and the document itself takes precedence over all profiles.
+// Mappings are merged recursively, scalars are overridden, and sequences are replaced
+// unless they are tagged with '!append', in which case they are appended to the inherited sequence.
+// file: The path of the file the document was read from.
+// root: The root mapping node of the document.
+// chain: The names of the profiles currently being resolved, used to detect cycles.
+func (l *profileLoader) resolve(file string, root *yaml.Node, chain []string) (*yaml.Node, []error) {
+	names, document, err := splitExtends(file, root)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var base *yaml.Node
+	if len(chain) == 0 {
+		base = l.defaults
+	}
+
+	var errs []error
+	for _, name := range names {
+		if slices.Contains(chain, name) {
+			errs = append(errs, fmt.Errorf("%s: cyclic profile reference: %s -> %s",
+				file, strings.Join(chain, " -> "), name))
+			continue
+		}
+
+		profile, pErrs := l.load(name, append(slices.Clone(chain), name))
+		if len(pErrs) > 0 {
+			errs = append(errs, pErrs...)
+			continue
+		}
+		base = mergeNodes(base, profile)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return mergeNodes(base, document), nil
+}
+
+// load reads, validates, and resolves the profile with the given name.
+// name: The name of the profile.
+// chain: The names of the profiles currently being resolved, including this one.
+func (l *profileLoader) load(name string, chain []string) (*yaml.Node, []error) {
+	if profile, ok := l.profiles[name]; ok {
+		return profile, nil
+	}
+
+	file := filepath.Join(l.dir, name+".yml")
+	b, rErr := os.ReadFile(file)
+	if rErr != nil {
+		return nil, []error{fmt.Errorf("%s: unknown profile '%s': %w", file, name, rErr)}
+	}
+
+	var document yaml.Node
+	if yErr := yaml.Unmarshal(b, &document); yErr != nil {
+		return nil, []error{fmt.Errorf("%s: %w", file, yErr)}
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 ||
+		document.Content[0].Kind != yaml.MappingNode {
+		return nil, []error{fmt.Errorf("%s: profile must be a mapping", file)}
+	}
+
+	profile, errs := l.resolve(file, document.Content[0], chain)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	_, own, _ := splitExtends(file, document.Content[0])
+	errs = validateFields(file, own, reflect.TypeFor[repository.Config](), "")
+	errs = append(errs, validateEnums(file, own)...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	l.profiles[name] = profile
+	return profile, nil
+}
+
+// splitExtends returns the profile names referenced by the 'extends' field and a copy of the document without it.
+// file: The path of the file the document was read from.
+// root: The root mapping node of the document.
+func splitExtends(file string, root *yaml.Node) ([]string, *yaml.Node, error) {
+	document := *root
+	document.Content = nil
+
+	var names []string
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value != extendsField {
+			document.Content = append(document.Content, key, value)
+			continue
+		}
+
+		switch value.Kind {
+		case yaml.ScalarNode:
+			names = append(names, value.Value)
+		case yaml.SequenceNode:
+			for _, element := range value.Content {
+				if element.Kind != yaml.ScalarNode {
+					return nil, nil, positionError(file, element, "profile names must be strings")
+				}
+				names = append(names, element.Value)
+			}
+		case yaml.DocumentNode, yaml.MappingNode, yaml.AliasNode:
+			return nil, nil, positionError(file, value, "field 'extends' must be a string or a list of strings")
+		default:
+			return nil, nil, positionError(file, value, "field 'extends' must be a string or a list of strings")
+		}
+	}
+
+	return names, &document, nil
+}
+
+// mergeNodes deep-merges the override node into the base node and returns the result.
+// Neither of the given nodes is modified.
+// base: The inherited node; may be nil.
+// override: The node taking precedence; may be nil.
+func mergeNodes(base *yaml.Node, override *yaml.Node) *yaml.Node {
+	if base == nil {
+		return normalizeNode(override)
+	}
+	if override == nil {
+		return normalizeNode(base)
+	}
+
+	switch {
+	case base.Kind == yaml.MappingNode && override.Kind == yaml.MappingNode:
+		merged := *override
+		merged.Content = nil
+		for i := 0; i+1 < len(base.Content); i += 2 {
+			merged.Content = append(merged.Content, base.Content[i], normalizeNode(base.Content[i+1]))
+		}
+
+		for i := 0; i+1 < len(override.Content); i += 2 {
+			key, value := override.Content[i], override.Content[i+1]
+			if index := keyIndex(&merged, key.Value); index >= 0 {
+				merged.Content[index+1] = mergeNodes(merged.Content[index+1], value)
+				continue
+			}
+			merged.Content = append(merged.Content, key, normalizeNode(value))
+		}
+		return &merged
+	case base.Kind == yaml.SequenceNode && override.Kind == yaml.SequenceNode && override.Tag == appendTag:
+		merged := *normalizeNode(override)
+		merged.Content = append(slices.Clone(normalizeNode(base).Content), merged.Content...)
+		return &merged
+	default:
+		return normalizeNode(override)
+	}
+}
+
+// keyIndex returns the index of the given key in a mapping node's content, or -1 if it does not exist.
+// node: The mapping node.
+// key: The key to look up.
+func keyIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeNode returns a copy of the given node with all '!append' tags replaced by the default sequence tag.
+// node: The node to normalize; may be nil.
+func normalizeNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+
+	normalized := *node
+	if normalized.Tag == appendTag {
+		normalized.Tag = sequenceTag
+	}
+	normalized.Content = make([]*yaml.Node, 0, len(node.Content))
+	for _, child := range node.Content {
+		normalized.Content = append(normalized.Content, normalizeNode(child))
+	}
+
+	return &normalized
+}
@@ -0,0 +1,410 @@
+//nolint:gochecknoglobals // globals are allowed in this file
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// wildcard matches any key of a mapping or any element of a sequence in a field path.
+const wildcard = "*"
+
+// requiredFields defines the field paths which must be present in every repository configuration.
+var requiredFields = [][]string{
+	{"name"},
+	{"rulesets", "branch", "enabled"},
+	{"rulesets", "custom", wildcard, "name"},
+	{"accessPermissions", "vault", "roles", wildcard, "name"},
+	{"accessPermissions", "vault", "paths", wildcard, "path"},
+	{"accessPermissions", "vault", "readFrom", wildcard, "repository"},
+	{"accessPermissions", "vault", "shareWith", wildcard, "repository"},
+	{"project", "fields", wildcard, "name"},
+	{"project", "fields", wildcard, "type"},
+}
+
+// enumField defines a field path and the values allowed for it.
+type enumField struct {
+	path   []string
+	values []string
+}
+
+// enumFields defines the enumerated fields of a repository configuration.
+var enumFields = []enumField{
+	{path: []string{"visibility"}, values: []string{"public", "private", "internal"}},
+	{
+		path:   []string{"accessPermissions", "google", "linkedProjects", wildcard, "accessLevel"},
+		values: []string{"default", "full"},
+	},
+	{
+		path:   []string{"accessPermissions", "scaleway", "linkedProjects", wildcard, "accessLevel"},
+		values: []string{"default", "full"},
+	},
+	{path: []string{"accessPermissions", "sink"}, values: []string{"vault", "secrets", "variables"}},
+	{
+		path:   []string{"accessPermissions", "vault", "auth", "tokenType"},
+		values: []string{"default", "service", "batch", "default-service", "default-batch"},
+	},
+	{path: []string{"rulesets", "custom", wildcard, "target"}, values: []string{"branch", "tag", "push"}},
+	{path: []string{"rulesets", "branch", "enforcement"}, values: []string{"active", "evaluate", "disabled"}},
+	{path: []string{"rulesets", "tag", "enforcement"}, values: []string{"active", "evaluate", "disabled"}},
+	{
+		path:   []string{"rulesets", "custom", wildcard, "enforcement"},
+		values: []string{"active", "evaluate", "disabled"},
+	},
+	{path: []string{"project", "fields", wildcard, "type"}, values: []string{"text", "number", "date", "singleSelect"}},
+}
+
+// builtinProjectFields defines the fields every GitHub project has, hence they cannot be added as custom fields.
+var builtinProjectFields = []string{
+	"Title", "Assignees", "Status", "Labels", "Linked pull requests", "Milestone", "Repository", "Reviewers",
+	"Parent issue", "Sub-issues progress",
+}
+
+// pushRules defines the fields of a custom ruleset restricting pushes, of which push rulesets require at least one.
+var pushRules = []string{"restrictedFilePaths", "maxFileSize", "restrictedFileExtensions"}
+
+// validateRepositoryNode validates a parsed repository configuration document against the schema of the given type.
+// It reports unknown fields, missing required fields, and invalid enumerated values with their position in the file.
+// file: The path of the file the document was read from.
+// document: The parsed YAML document.
+// schema: The type the document is decoded into.
+func validateRepositoryNode(file string, document *yaml.Node, schema reflect.Type) []error {
+	root := document
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return []error{positionError(file, root, "repository configuration must be a mapping")}
+	}
+
+	errs := validateFields(file, root, schema, "")
+
+	for _, path := range requiredFields {
+		errs = append(errs, validateRequired(file, root, path, 0)...)
+	}
+
+	errs = append(errs, validateEnums(file, root)...)
+
+	errs = append(errs, validateCustomRulesets(file, root)...)
+
+	return append(errs, validateProjectFields(file, root)...)
+}
+
+// validateCustomRulesets checks that the custom rulesets have unique names, and that they apply to anything:
+// branch and tag rulesets require at least one pattern, and push rulesets at least one push rule.
+// file: The path of the file the document was read from.
+// root: The root mapping node of the document.
+func validateCustomRulesets(file string, root *yaml.Node) []error {
+	var errs []error
+	names := make(map[string]bool)
+	for _, ruleset := range findNodes(root, []string{"rulesets", "custom", wildcard}) {
+		name := lookup(ruleset, "name")
+		if name == nil || name.Value == "" {
+			// reported as a missing required field
+			continue
+		}
+		if names[name.Value] {
+			errs = append(errs, positionError(file, name, fmt.Sprintf("duplicate custom ruleset '%s'", name.Value)))
+		}
+		names[name.Value] = true
+
+		target := "branch"
+		if t := lookup(ruleset, "target"); t != nil {
+			target = t.Value
+		}
+		hasPushRule := slices.ContainsFunc(pushRules, func(field string) bool {
+			return !isEmpty(lookup(ruleset, field))
+		})
+		switch target {
+		case "push":
+			if !hasPushRule {
+				errs = append(errs, positionError(file, ruleset, fmt.Sprintf(
+					"custom ruleset '%s' with target 'push' requires at least one of: %s",
+					name.Value,
+					strings.Join(pushRules, ", "),
+				)))
+			}
+			for _, field := range []string{"patterns", "excludePatterns"} {
+				if node := lookup(ruleset, field); !isEmpty(node) {
+					errs = append(errs, positionError(file, node, fmt.Sprintf(
+						"custom ruleset '%s' with target 'push' does not support '%s'", name.Value, field,
+					)))
+				}
+			}
+		case "branch", "tag":
+			if isEmpty(lookup(ruleset, "patterns")) {
+				errs = append(errs, positionError(file, ruleset, fmt.Sprintf(
+					"custom ruleset '%s' with target '%s' requires at least one pattern in 'patterns'",
+					name.Value,
+					target,
+				)))
+			}
+			if hasPushRule {
+				errs = append(errs, positionError(file, ruleset, fmt.Sprintf(
+					"custom ruleset '%s' with target '%s' does not support push rules (%s)",
+					name.Value,
+					target,
+					strings.Join(pushRules, ", "),
+				)))
+			}
+		default:
+			// reported as an invalid enumerated value
+		}
+	}
+
+	return errs
+}
+
+// validateProjectFields checks that the custom fields of the project have unique names which are not the names
+// of built-in fields, and that exactly the single select fields have options.
+// file: The path of the file the document was read from.
+// root: The root mapping node of the document.
+func validateProjectFields(file string, root *yaml.Node) []error {
+	var errs []error
+	names := make(map[string]bool)
+	for _, field := range findNodes(root, []string{"project", "fields", wildcard}) {
+		name := lookup(field, "name")
+		if name == nil || name.Value == "" {
+			// reported as a missing required field
+			continue
+		}
+		if names[name.Value] {
+			errs = append(errs, positionError(file, name, fmt.Sprintf("duplicate project field '%s'", name.Value)))
+		}
+		names[name.Value] = true
+		if slices.Contains(builtinProjectFields, name.Value) {
+			errs = append(errs, positionError(file, name, fmt.Sprintf(
+				"project field '%s' is a built-in field; the options of 'Status' are set via 'project.columns'",
+				name.Value,
+			)))
+		}
+
+		fieldType := lookup(field, "type")
+		options := lookup(field, "options")
+		switch {
+		case fieldType == nil:
+			// reported as a missing required field
+		case fieldType.Value == "singleSelect" && isEmpty(options):
+			errs = append(errs, positionError(file, field, fmt.Sprintf(
+				"project field '%s' with type 'singleSelect' requires at least one option in 'options'", name.Value,
+			)))
+		case fieldType.Value != "singleSelect" && !isEmpty(options):
+			errs = append(errs, positionError(file, options, fmt.Sprintf(
+				"project field '%s' with type '%s' does not support 'options'", name.Value, fieldType.Value,
+			)))
+		}
+	}
+
+	return errs
+}
+
+// validateEnums checks that all enumerated fields below the given node have an allowed value.
+// file: The path of the file the node was read from.
+// root: The root mapping node of the document.
+func validateEnums(file string, root *yaml.Node) []error {
+	var errs []error
+	for _, field := range enumFields {
+		for _, node := range findNodes(root, field.path) {
+			if node.Kind != yaml.ScalarNode || !slices.Contains(field.values, node.Value) {
+				errs = append(errs, positionError(file, node, fmt.Sprintf(
+					"invalid value '%s' for field '%s'; allowed values: %s",
+					node.Value,
+					strings.Join(field.path, "."),
+					strings.Join(field.values, ", "),
+				)))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateFields recursively checks that all keys of the given node are known fields of the given type.
+// file: The path of the file the node was read from.
+// node: The YAML node to validate.
+// schema: The type the node is decoded into.
+// path: The field path of the node, used in error messages.
+func validateFields(file string, node *yaml.Node, schema reflect.Type, path string) []error {
+	for schema.Kind() == reflect.Pointer {
+		schema = schema.Elem()
+	}
+
+	var errs []error
+	switch schema.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return []error{positionError(file, node, fmt.Sprintf("field '%s' must be a mapping", path))}
+		}
+
+		fields := make(map[string]reflect.Type)
+		for i := range schema.NumField() {
+			field := schema.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name != "" && name != "-" {
+				fields[name] = field.Type
+			}
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldPath := joinPath(path, key.Value)
+			fieldType, ok := fields[key.Value]
+			if !ok {
+				errs = append(errs, positionError(file, key, fmt.Sprintf("unknown field '%s'", fieldPath)))
+				continue
+			}
+			errs = append(errs, validateFields(file, value, fieldType, fieldPath)...)
+		}
+	case reflect.Slice:
+		if node.Kind == yaml.SequenceNode {
+			for i, element := range node.Content {
+				errs = append(errs, validateFields(file, element, schema.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case reflect.Map:
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				errs = append(errs, validateFields(
+					file,
+					node.Content[i+1],
+					schema.Elem(),
+					joinPath(path, node.Content[i].Value),
+				)...)
+			}
+		}
+	default:
+		// scalar values are checked when decoding
+	}
+
+	return errs
+}
+
+// validateRequired checks that the given field path exists below the given node.
+// Wildcard segments require the remaining path to exist for every element they match.
+// file: The path of the file the node was read from.
+// node: The YAML node to start from.
+// path: The required field path.
+// depth: The index of the current path segment.
+func validateRequired(file string, node *yaml.Node, path []string, depth int) []error {
+	if depth == len(path) {
+		return nil
+	}
+
+	segment := path[depth]
+	if segment == wildcard {
+		var errs []error
+		for _, child := range children(node) {
+			errs = append(errs, validateRequired(file, child, path, depth+1)...)
+		}
+		return errs
+	}
+
+	// optional parents of a wildcard segment do not need to exist
+	if slices.Contains(path[depth:], wildcard) && lookup(node, segment) == nil {
+		return nil
+	}
+
+	child := lookup(node, segment)
+	if child == nil || (child.Kind == yaml.ScalarNode && child.Value == "") {
+		return []error{
+			positionError(file, node, fmt.Sprintf("missing required field '%s'", strings.Join(path, "."))),
+		}
+	}
+
+	return validateRequired(file, child, path, depth+1)
+}
+
+// findNodes returns all nodes matching the given field path below the given node.
+// node: The YAML node to start from.
+// path: The field path, which may contain wildcard segments.
+func findNodes(node *yaml.Node, path []string) []*yaml.Node {
+	if len(path) == 0 {
+		return []*yaml.Node{node}
+	}
+
+	if path[0] == wildcard {
+		var nodes []*yaml.Node
+		for _, child := range children(node) {
+			nodes = append(nodes, findNodes(child, path[1:])...)
+		}
+		return nodes
+	}
+
+	child := lookup(node, path[0])
+	if child == nil {
+		return nil
+	}
+	return findNodes(child, path[1:])
+}
+
+// lookup returns the value of the given key in a mapping node, or nil if it does not exist.
+// node: The mapping node.
+// key: The key to look up.
+func lookup(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// children returns the values of a mapping node or the elements of a sequence node.
+// node: The YAML node.
+func children(node *yaml.Node) []*yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		var values []*yaml.Node
+		for i := 1; i < len(node.Content); i += 2 {
+			values = append(values, node.Content[i])
+		}
+		return values
+	case yaml.SequenceNode:
+		return node.Content
+	case yaml.DocumentNode, yaml.ScalarNode, yaml.AliasNode:
+		return nil
+	default:
+		return nil
+	}
+}
+
+// isEmpty returns whether the given node is missing, an empty scalar, or an empty sequence or mapping.
+// node: The YAML node.
+func isEmpty(node *yaml.Node) bool {
+	if node == nil {
+		return true
+	}
+	if node.Kind == yaml.ScalarNode {
+		return node.Value == "" || node.Tag == "!!null"
+	}
+	return len(node.Content) == 0
+}
+
+// joinPath joins a parent field path and a field name.
+// parent: The parent field path.
+// name: The field name.
+func joinPath(parent string, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// positionError creates an error prefixed with the file path and the position of the given node.
+// Nodes without a position, e.g. those of the repository defaults, are reported without one.
+// file: The path of the file the node was read from.
+// node: The YAML node the error refers to.
+// msg: The error message.
+func positionError(file string, node *yaml.Node, msg string) error {
+	if node.Line == 0 {
+		return fmt.Errorf("%s: %s", file, msg)
+	}
+	return fmt.Errorf("%s:%d:%d: %s", file, node.Line, node.Column, msg)
+}
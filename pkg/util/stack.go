@@ -0,0 +1,88 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/muhlba91/github-infrastructure/pkg/model/config/stack"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// secureField is the field Pulumi uses to store encrypted configuration values.
+	secureField = "secure"
+	// secretPlaceholder replaces encrypted configuration values which cannot be decrypted offline.
+	secretPlaceholder = "[secret]"
+)
+
+// ParseStackConfig reads the project configuration of a Pulumi stack from its stack file.
+// It does not connect to any backend, hence encrypted values are not decrypted but replaced by a placeholder.
+// dir: The directory containing the Pulumi project file.
+// stackName: The name of the stack.
+func ParseStackConfig(dir string, stackName string) (*stack.Config, error) {
+	projectFile := filepath.Join(dir, "Pulumi.yaml")
+	pb, pErr := os.ReadFile(projectFile)
+	if pErr != nil {
+		log.Err(pErr).Msgf("[stack] error reading project file: %s", projectFile)
+		return nil, pErr
+	}
+
+	var project struct {
+		Name string `yaml:"name"`
+	}
+	if yErr := yaml.Unmarshal(pb, &project); yErr != nil {
+		return nil, fmt.Errorf("%s: %w", projectFile, yErr)
+	}
+
+	stackFile := filepath.Join(dir, fmt.Sprintf("Pulumi.%s.yaml", stackName))
+	sb, sErr := os.ReadFile(stackFile)
+	if sErr != nil {
+		log.Err(sErr).Msgf("[stack] error reading stack file: %s", stackFile)
+		return nil, sErr
+	}
+
+	var stackDocument struct {
+		Config map[string]yaml.Node `yaml:"config"`
+	}
+	if yErr := yaml.Unmarshal(sb, &stackDocument); yErr != nil {
+		return nil, fmt.Errorf("%s: %w", stackFile, yErr)
+	}
+
+	projectConfig := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for key, value := range stackDocument.Config {
+		name, ok := strings.CutPrefix(key, project.Name+":")
+		if !ok {
+			continue
+		}
+		projectConfig.Content = append(projectConfig.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name},
+			redactSecrets(&value),
+		)
+	}
+
+	var config stack.Config
+	if dErr := projectConfig.Decode(&config); dErr != nil {
+		return nil, fmt.Errorf("%s: %w", stackFile, dErr)
+	}
+
+	return &config, nil
+}
+
+// redactSecrets returns a copy of the given node with all encrypted values replaced by a placeholder.
+// node: The configuration node.
+func redactSecrets(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.MappingNode && len(node.Content) == 2 && node.Content[0].Value == secureField {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: secretPlaceholder}
+	}
+
+	redacted := *node
+	redacted.Content = make([]*yaml.Node, 0, len(node.Content))
+	for _, child := range node.Content {
+		redacted.Content = append(redacted.Content, redactSecrets(child))
+	}
+
+	return &redacted
+}
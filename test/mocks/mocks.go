@@ -0,0 +1,142 @@
+package mocks
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+const (
+	// stackReferenceType is the type token of Pulumi stack references.
+	stackReferenceType = "pulumi:pulumi:StackReference"
+	// randomStringType is the type token of random strings.
+	randomStringType = "random:index/randomString:RandomString"
+	// secretPlaceholder replaces secret values in the recorded inputs.
+	secretPlaceholder = "[secret]"
+	// unknownPlaceholder replaces unknown values in the recorded inputs.
+	unknownPlaceholder = "[unknown]"
+)
+
+// Resource is a resource registered during a mocked Pulumi program run.
+type Resource struct {
+	// Type is the type token of the resource.
+	Type string `json:"type"`
+	// Name is the logical name of the resource.
+	Name string `json:"name"`
+	// Parent is the logical name of the parent resource, if it is not the stack.
+	Parent string `json:"parent,omitempty"`
+	// Inputs are the inputs of the resource with secrets and unknown values replaced by placeholders.
+	Inputs map[string]any `json:"inputs"`
+}
+
+// Mocks is a Pulumi resource monitor recording every registered resource.
+// Resources echo their inputs as outputs, stack references resolve to the configured outputs,
+// and random strings resolve to deterministic values.
+type Mocks struct {
+	mu           sync.Mutex
+	resources    []Resource
+	stackOutputs map[string]map[string]any
+}
+
+// New creates a new resource monitor.
+// stackOutputs: The outputs of referenced stacks keyed by their project name.
+func New(stackOutputs map[string]map[string]any) *Mocks {
+	return &Mocks{
+		stackOutputs: stackOutputs,
+	}
+}
+
+// NewResource records the resource and returns its ID and state.
+// args: The arguments of the resource registration.
+func (m *Mocks) NewResource(args pulumi.MockResourceArgs) (string, resource.PropertyMap, error) {
+	var parent string
+	if args.RegisterRPC != nil && args.RegisterRPC.GetParent() != "" {
+		parentURN := resource.URN(args.RegisterRPC.GetParent())
+		if parentURN.QualifiedType() != resource.RootStackType {
+			parent = parentURN.Name()
+		}
+	}
+
+	m.mu.Lock()
+	m.resources = append(m.resources, Resource{
+		Type:   args.TypeToken,
+		Name:   args.Name,
+		Parent: parent,
+		Inputs: redact(args.Inputs),
+	})
+	m.mu.Unlock()
+
+	state := args.Inputs.Copy()
+	switch args.TypeToken {
+	case stackReferenceType:
+		// stack references are named <organization>/<project>/<stack>
+		name := strings.Split(args.Inputs["name"].StringValue(), "/")
+		state["outputs"] = resource.NewObjectProperty(
+			resource.NewPropertyMapFromMap(m.stackOutputs[name[min(1, len(name)-1)]]),
+		)
+	case randomStringType:
+		if length := args.Inputs["length"]; length.IsNumber() {
+			state["result"] = resource.NewStringProperty(strings.Repeat("a", int(length.NumberValue())))
+		}
+	}
+
+	id := args.ID
+	if id == "" {
+		id = fmt.Sprintf("%s-id", args.Name)
+	}
+	return id, state, nil
+}
+
+// Call returns the arguments of the invoke as its result.
+// args: The arguments of the invoke.
+func (m *Mocks) Call(args pulumi.MockCallArgs) (resource.PropertyMap, error) {
+	return args.Args, nil
+}
+
+// Resources returns the recorded resources sorted by their type and name.
+func (m *Mocks) Resources() []Resource {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	resources := slices.Clone(m.resources)
+	slices.SortFunc(resources, func(a, b Resource) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.Name, b.Name))
+	})
+	return resources
+}
+
+// redact converts the given inputs to plain values.
+// Secrets and unknown values are replaced by placeholders, and resource references by their URNs.
+// inputs: The inputs of a resource.
+func redact(inputs resource.PropertyMap) map[string]any {
+	return inputs.MapRepl(nil, redactValue)
+}
+
+// redactValue replaces secret, unknown, and resource reference values.
+// value: The value to replace.
+func redactValue(value resource.PropertyValue) (any, bool) {
+	switch {
+	case value.IsSecret():
+		return secretPlaceholder, true
+	case value.IsComputed():
+		return unknownPlaceholder, true
+	case value.IsOutput():
+		output := value.OutputValue()
+		if output.Secret {
+			return secretPlaceholder, true
+		}
+		if !output.Known {
+			return unknownPlaceholder, true
+		}
+		return output.Element.MapRepl(nil, redactValue), true
+	case value.IsResourceReference():
+		return string(value.ResourceReferenceValue().URN), true
+	default:
+		return nil, false
+	}
+}